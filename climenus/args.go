@@ -0,0 +1,186 @@
+package climenus
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Specifier for an argument taken by a command, used to validate and convert
+// the argument before the command's Run function is called
+type ArgSpec struct {
+	Name        string   // name of the argument, used to look up its value and shown in usage
+	Type        string   // type the argument is converted to (string, int, float), string if empty
+	Required    bool     // whether the argument must be given
+	Default     string   // value used when an optional argument isn't given, converted like input
+	HasDefault  bool     // whether Default is used, so "" can be a default. Set if Default isn't ""
+	Choices     []string // allowed values for the argument, any value is allowed if empty
+	Description string   // description of the argument, shown in help
+	// returns values for the argument starting with prefix, offered with Choices
+	// for tab completion by the line editor. May be nil
+	Complete func(prefix string) []string
+}
+
+// struct holding the arguments a command was issued with,
+// after they were validated and converted using the command's ArgSpecs
+type Args struct {
+	Command *Command               // command the arguments were given to
+	Raw     []string               // the input split into words, Raw[0] is the command as entered
+	Values  map[string]interface{} // converted argument values by ArgSpec name
+}
+
+// error for input that doesn't match a command's ArgSpecs,
+// includes the command usage in its message
+type UsageError struct {
+	Command *Command // command the input was given to
+	Err     error    // what was wrong with the input
+}
+
+func (e *UsageError) Error() string {
+	return fmt.Sprintf("%s\nusage: %s", e.Err.Error(), e.Command.Usage())
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// returns the usage for this command, e.g. "scale <factor> [unit]",
+// with required arguments in angle brackets and optional ones in square brackets
+func (command *Command) Usage() string {
+	parts := []string{command.Name}
+	if command.Name == "" {
+		parts[0] = fmt.Sprint(command.OptionNumber)
+	}
+
+	for _, spec := range command.ArgSpecs {
+		name := spec.Name
+		if len(spec.Choices) > 0 {
+			name += "=" + strings.Join(spec.Choices, "|")
+		}
+		if spec.Required {
+			parts = append(parts, "<"+name+">")
+		} else {
+			parts = append(parts, "["+name+"]")
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// validates the input words for this command against its ArgSpecs, and converts
+// each argument (or its default) to the type of its spec, as is done before Run is called.
+// words[0] is the command as entered, the arguments follow it, see Tokenize.
+// Returns a *UsageError if the words don't match the ArgSpecs
+func (command *Command) ParseArgs(words []string) (*Args, error) {
+	args := &Args{Command: command, Raw: words, Values: make(map[string]interface{})}
+	given := words[1:]
+
+	if len(given) > 0 && len(command.ArgSpecs) == 0 {
+		return nil, &UsageError{command, errors.New("command takes no arguments")}
+	}
+	if len(given) > len(command.ArgSpecs) {
+		return nil, &UsageError{command, fmt.Errorf("too many arguments, expected at most %d", len(command.ArgSpecs))}
+	}
+
+	for i, spec := range command.ArgSpecs {
+		input := spec.Default
+		if i < len(given) {
+			input = given[i]
+		} else if spec.Required {
+			return nil, &UsageError{command, fmt.Errorf("missing argument %s", spec.Name)}
+		} else if !spec.hasDefault() {
+			continue
+		}
+
+		value, err := spec.convert(input)
+		if err != nil {
+			return nil, &UsageError{command, err}
+		}
+		args.Values[spec.Name] = value
+	}
+
+	return args, nil
+}
+
+// returns true if the spec's Default is used when the argument isn't given
+func (spec *ArgSpec) hasDefault() bool {
+	return spec.HasDefault || spec.Default != ""
+}
+
+// checks the input against the spec's choices and converts it to the spec's type
+func (spec *ArgSpec) convert(input string) (interface{}, error) {
+	if len(spec.Choices) > 0 && !slices.Contains(spec.Choices, input) {
+		return nil, fmt.Errorf("invalid value %q for %s, must be one of %s",
+			input, spec.Name, strings.Join(spec.Choices, ", "))
+	}
+
+	argType := spec.Type
+	if argType == "" {
+		argType = StringType
+	}
+
+	value, ok := convertToType(argType, input)
+	if !ok {
+		return nil, fmt.Errorf("invalid value %q for %s, must be of type %s", input, spec.Name, argType)
+	}
+
+	return value, nil
+}
+
+// returns true if the argument was given, or has a default value
+func (args *Args) Has(name string) bool {
+	_, ok := args.Values[name]
+	return ok
+}
+
+// returns the value of a string argument, or "" if it wasn't given
+func (args *Args) String(name string) string {
+	value, _ := args.Values[name].(string)
+	return value
+}
+
+// returns the value of an int argument, or 0 if it wasn't given
+func (args *Args) Int(name string) int {
+	value, _ := args.Values[name].(int)
+	return value
+}
+
+// returns the value of a float argument, or 0 if it wasn't given
+func (args *Args) Float(name string) float64 {
+	value, _ := args.Values[name].(float64)
+	return value
+}
+
+// calls the function for this command with the input words (words[0] being the command
+// as entered), through the middleware of the menu, see Middleware. Run is called with the
+// parsed args if set, otherwise Execute with the raw words. Returns whether the command
+// was called, which it isn't if the args aren't valid or middleware doesn't call next
+func (command *Command) execute(words []string, menu *Menu) (bool, error) {
+	args := &Args{Command: command, Raw: words, Values: make(map[string]interface{})}
+	if command.Run != nil {
+		var err error
+		args, err = command.ParseArgs(words)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	called := false
+	handler := menu.handler(func(command *Command, args *Args, menu *Menu) error {
+		err := callCommand(command, args, menu)
+		called = true
+		return err
+	})
+	return called, handler(command, args, menu)
+}
+
+// returns an error if the input words aren't valid args for the command
+func (command *Command) validateArgs(words []string) error {
+	if command.Run == nil {
+		return nil
+	}
+
+	_, err := command.ParseArgs(words)
+	return err
+}
@@ -0,0 +1,131 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var scaleArgSpecs = []ArgSpec{
+	{Name: "factor", Type: FloatType, Required: true},
+	{Name: "unit", Choices: []string{"g", "kg"}, Default: "g"},
+	{Name: "servings", Type: IntType},
+}
+
+func TestParseArgs(t *testing.T) {
+	testCases := []struct {
+		name           string
+		words          []string
+		expectedValues map[string]interface{}
+		expectedErr    string
+	}{
+		{
+			name:           "testRequiredAndDefault",
+			words:          []string{"scale", "1.5"},
+			expectedValues: map[string]interface{}{"factor": 1.5, "unit": "g"},
+		},
+		{
+			name:           "testAllArgs",
+			words:          []string{"scale", "2", "kg", "4"},
+			expectedValues: map[string]interface{}{"factor": 2.0, "unit": "kg", "servings": 4},
+		},
+		{
+			name:        "testMissingRequired",
+			words:       []string{"scale"},
+			expectedErr: "missing argument factor\nusage: scale <factor> [unit=g|kg] [servings]",
+		},
+		{
+			name:        "testWrongType",
+			words:       []string{"scale", "lots"},
+			expectedErr: `invalid value "lots" for factor, must be of type float`,
+		},
+		{
+			name:        "testInvalidChoice",
+			words:       []string{"scale", "2", "lb"},
+			expectedErr: `invalid value "lb" for unit, must be one of g, kg`,
+		},
+		{
+			name:        "testTooManyArgs",
+			words:       []string{"scale", "2", "kg", "4", "extra"},
+			expectedErr: "too many arguments, expected at most 3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			command := Command{Name: "scale", ArgSpecs: scaleArgSpecs}
+
+			args, err := command.ParseArgs(tc.words)
+			if tc.expectedErr != "" {
+				var usageErr *UsageError
+				if !errors.As(err, &usageErr) {
+					t.Fatalf("got error %v, expected a UsageError", err)
+				}
+				if !strings.HasPrefix(err.Error(), tc.expectedErr) {
+					t.Errorf("got %q, expected %q", err.Error(), tc.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("got error %v", err.Error())
+			}
+			if len(args.Values) != len(tc.expectedValues) {
+				t.Errorf("got %v, expected %v", args.Values, tc.expectedValues)
+			}
+			for name, expected := range tc.expectedValues {
+				if args.Values[name] != expected {
+					t.Errorf("%v: got %v, expected %v", name, args.Values[name], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestParseArgsEmptyDefault(t *testing.T) {
+	command := Command{Name: "note", ArgSpecs: []ArgSpec{
+		{Name: "text", HasDefault: true},
+		{Name: "tag"},
+	}}
+
+	args, err := command.ParseArgs([]string{"note"})
+	if err != nil {
+		t.Fatalf("got error %v", err.Error())
+	}
+	// the empty default is given, the argument without a default isn't
+	if !args.Has("text") || args.String("text") != "" || args.Has("tag") {
+		t.Errorf("got %v, expected only text to have a value", args.Values)
+	}
+
+	expected := `(string, optional, default "")`
+	if summary := command.ArgSpecs[0].summary(); summary != expected {
+		t.Errorf("got %q, expected %q", summary, expected)
+	}
+}
+
+func TestMenuLoopRun(t *testing.T) {
+	var out bytes.Buffer
+	var menu Menu
+	menu.Session = NewSession(strings.NewReader("scale\n1 3 kg\nback\n"), &out)
+
+	var factor float64
+	var unit string
+	menu.AddCommand(&Command{Name: "scale", ArgSpecs: scaleArgSpecs, Run: func(args *Args, m *Menu) error {
+		factor = args.Float("factor")
+		unit = args.String("unit")
+		return nil
+	}})
+
+	err := menu.MenuLoop()
+	if err != nil {
+		t.Errorf("got error %v", err.Error())
+	}
+
+	if factor != 3 || unit != "kg" {
+		t.Errorf("got %v %v, expected %v %v", factor, unit, 3, "kg")
+	}
+	if !strings.Contains(out.String(), "missing argument factor\nusage: scale <factor>") {
+		t.Errorf("output %q does not contain usage error", out.String())
+	}
+}
@@ -0,0 +1,148 @@
+package climenus
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// struct holding the characters used to draw the borders of a menu's table.
+// Menus without a BorderStyle are drawn with a line of dashes under the header.
+type BorderStyle struct {
+	Horizontal string // horizontal lines, none are drawn if empty
+	Vertical   string // lines between and around columns, none are drawn if empty
+
+	TopLeft, TopJoin, TopRight          string // corners and joins of the line above the header
+	MiddleLeft, MiddleJoin, MiddleRight string // corners and joins of the lines under the header and between rows
+	BottomLeft, BottomJoin, BottomRight string // corners and joins of the line below the last row
+
+	Top      bool // whether a line is drawn above the header
+	Bottom   bool // whether a line is drawn below the last row
+	RowRules bool // whether lines are drawn between commands when a command takes up several lines
+}
+
+// borders without any lines, columns are only separated by spaces
+var NoBorder = &BorderStyle{}
+
+// grid drawn with ASCII characters
+var ASCIIBorder = &BorderStyle{
+	Horizontal: "-", Vertical: "|",
+	TopLeft: "+", TopJoin: "+", TopRight: "+",
+	MiddleLeft: "+", MiddleJoin: "+", MiddleRight: "+",
+	BottomLeft: "+", BottomJoin: "+", BottomRight: "+",
+	Top: true, Bottom: true, RowRules: true,
+}
+
+// grid drawn with light box-drawing characters
+var LightBorder = &BorderStyle{
+	Horizontal: "─", Vertical: "│",
+	TopLeft: "┌", TopJoin: "┬", TopRight: "┐",
+	MiddleLeft: "├", MiddleJoin: "┼", MiddleRight: "┤",
+	BottomLeft: "└", BottomJoin: "┴", BottomRight: "┘",
+	Top: true, Bottom: true, RowRules: true,
+}
+
+// grid drawn with heavy box-drawing characters
+var HeavyBorder = &BorderStyle{
+	Horizontal: "━", Vertical: "┃",
+	TopLeft: "┏", TopJoin: "┳", TopRight: "┓",
+	MiddleLeft: "┣", MiddleJoin: "╋", MiddleRight: "┫",
+	BottomLeft: "┗", BottomJoin: "┻", BottomRight: "┛",
+	Top: true, Bottom: true, RowRules: true,
+}
+
+// grid drawn with light box-drawing characters and rounded corners
+var RoundedBorder = &BorderStyle{
+	Horizontal: "─", Vertical: "│",
+	TopLeft: "╭", TopJoin: "┬", TopRight: "╮",
+	MiddleLeft: "├", MiddleJoin: "┼", MiddleRight: "┤",
+	BottomLeft: "╰", BottomJoin: "┴", BottomRight: "╯",
+	Top: true, Bottom: true, RowRules: true,
+}
+
+// table drawn like a markdown table, with a line only under the header
+var MarkdownBorder = &BorderStyle{
+	Horizontal: "-", Vertical: "|",
+	MiddleLeft: "|", MiddleJoin: "|", MiddleRight: "|",
+}
+
+// returns the border style for this menu, falling back to the border style of the menu
+// it was opened from as a SubMenu. Returns nil if there is none
+func (menu *Menu) border() *BorderStyle {
+	for m := menu; m != nil; m = m.parent {
+		if m.Border != nil {
+			return m.Border
+		}
+	}
+	return nil
+}
+
+// returns a line of the table made of cells, which include the space after their
+// contents. Cells are styled with cellStyle, and the borders with borderStyle.
+// A nil border style joins the cells without borders.
+func (b *BorderStyle) line(cells []string, cellStyle Style, borderStyle Style) string {
+	if b == nil || b.Vertical == "" {
+		return cellStyle.Render(strings.Join(cells, ""))
+	}
+
+	var sb strings.Builder
+	for _, cell := range cells {
+		sb.WriteString(borderStyle.Render(b.Vertical + " "))
+		sb.WriteString(cellStyle.Render(cell))
+	}
+	sb.WriteString(borderStyle.Render(b.Vertical))
+	return sb.String()
+}
+
+// returns the width the border's lines add to a table of n columns, on top of
+// the space after each column
+func (b *BorderStyle) extraWidth(n int) int {
+	if b == nil || b.Vertical == "" {
+		return 0
+	}
+	// a line before each column and after the last, and a space after each line but the last
+	return (n+1)*displayWidth(b.Vertical) + n
+}
+
+// returns a horizontal line across the columns using the given corners and joins,
+// or "" if the style has no horizontal lines
+func (b *BorderStyle) rule(columns []MenuColumn, left string, join string, right string) string {
+	if b.Horizontal == "" {
+		return ""
+	}
+
+	segments := make([]string, len(columns))
+	for i, column := range columns {
+		width := int(math.Abs(float64(column.ColWidth)))
+		// lines between columns have a space on each side of the cell
+		if b.Vertical != "" {
+			width += 2
+		} else {
+			width += 1
+		}
+		segments[i] = strings.Repeat(b.Horizontal, width)
+	}
+
+	if b.Vertical == "" {
+		return strings.Join(segments, "")
+	}
+	return left + strings.Join(segments, join) + right
+}
+
+// prints a horizontal line of the border in the given style, nothing if the line is empty
+func printRule(out io.Writer, rule string, style Style) {
+	if rule != "" {
+		fmt.Fprintln(out, style.Render(rule))
+	}
+}
+
+// returns true if any of the commands takes up more than one line in the columns
+func hasMultiLineRows(columns []MenuColumn, commands []*Command) bool {
+	for _, command := range commands {
+		if _, height := getSplits(&columns, command); height > 1 {
+			return true
+		}
+	}
+	return false
+}
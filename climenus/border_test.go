@@ -0,0 +1,150 @@
+package climenus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// names of the commands of the border tests, one fits on a line and one is wrapped
+var borderTestCommands = []string{"add", "new item"}
+
+func TestShowMenuWithBorder(t *testing.T) {
+	testCases := []struct {
+		name     string
+		border   *BorderStyle
+		expected []string
+	}{
+		{
+			name:   "testNoBorder",
+			border: NoBorder,
+			expected: []string{
+				" # Name  ",
+				" 1 add   ",
+				" 2 new   ",
+				"   item  ",
+			},
+		},
+		{
+			name:   "testASCIIBorder",
+			border: ASCIIBorder,
+			expected: []string{
+				"+----+-------+",
+				"|  # | Name  |",
+				"+----+-------+",
+				"|  1 | add   |",
+				"+----+-------+",
+				"|  2 | new   |",
+				"|    | item  |",
+				"+----+-------+",
+			},
+		},
+		{
+			name:   "testRoundedBorder",
+			border: RoundedBorder,
+			expected: []string{
+				"╭────┬───────╮",
+				"│  # │ Name  │",
+				"├────┼───────┤",
+				"│  1 │ add   │",
+				"├────┼───────┤",
+				"│  2 │ new   │",
+				"│    │ item  │",
+				"╰────┴───────╯",
+			},
+		},
+		{
+			name:   "testMarkdownBorder",
+			border: MarkdownBorder,
+			expected: []string{
+				"|  # | Name  |",
+				"|----|-------|",
+				"|  1 | add   |",
+				"|  2 | new   |",
+				"|    | item  |",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			menu := newTestMenu("", &out, borderTestCommands...)
+			menu.Border = tc.border
+			menu.ShowMenu()
+			expected := "\n\n\n" + strings.Join(tc.expected, "\n") + "\n"
+			if out.String() != expected {
+				t.Errorf("got %q, expected %q", out.String(), expected)
+			}
+		})
+	}
+}
+
+func TestBorderWithoutRowRules(t *testing.T) {
+	var out bytes.Buffer
+	menu := newTestMenu("", &out, borderTestCommands...)
+	menu.Border = HeavyBorder
+	// no lines are drawn between commands that each fit on one line
+	menu.Commands[1].Name = "edit"
+	menu.ShowMenu()
+
+	expected := "\n\n\n" +
+		"┏━━━━┳━━━━━━━┓\n" +
+		"┃  # ┃ Name  ┃\n" +
+		"┣━━━━╋━━━━━━━┫\n" +
+		"┃  1 ┃ add   ┃\n" +
+		"┃  2 ┃ edit  ┃\n" +
+		"┗━━━━┻━━━━━━━┛\n"
+	if out.String() != expected {
+		t.Errorf("got %q, expected %q", out.String(), expected)
+	}
+}
+
+func TestBorderInheritedBySubMenu(t *testing.T) {
+	var out bytes.Buffer
+	parent := newTestMenu("", &out, borderTestCommands...)
+	parent.Border = LightBorder
+	subMenu := &Menu{parent: parent}
+	if subMenu.border() != LightBorder {
+		t.Errorf("submenu doesn't use the border of its parent")
+	}
+
+	subMenu.Border = NoBorder
+	if subMenu.border() != NoBorder {
+		t.Errorf("submenu doesn't use its own border")
+	}
+}
+
+func TestBorderStyled(t *testing.T) {
+	var out bytes.Buffer
+	menu := newTestMenu("", &out, borderTestCommands...)
+	menu.Border = MarkdownBorder
+	menu.Theme = DefaultTheme
+	menu.Session.Color = ColorAlways
+	menu.Commands = menu.Commands[:1]
+	menu.ShowMenu()
+
+	expected := "\n\n\n" +
+		"\x1b[2m| \x1b[0m\x1b[1m # \x1b[0m\x1b[2m| \x1b[0m\x1b[1mName  \x1b[0m\x1b[2m|\x1b[0m\n" +
+		"\x1b[2m|----|-------|\x1b[0m\n" +
+		"\x1b[2m| \x1b[0m\x1b[1;36m 1 \x1b[0m\x1b[2m| \x1b[0madd   \x1b[2m|\x1b[0m\n"
+	if out.String() != expected {
+		t.Errorf("got %q, expected %q", out.String(), expected)
+	}
+}
+
+func TestBorderLayout(t *testing.T) {
+	t.Setenv(columnsEnv, "30")
+	var out bytes.Buffer
+	menu := newTestMenu("", &out, borderTestCommands...)
+	menu.Border = LightBorder
+	menu.Columns[1] = MenuColumn{MinWidth: 5, Type: StringType, Label: "Name", Align: AlignLeft}
+	menu.ShowMenu()
+
+	// the flexible column leaves room for the lines so the table fits the terminal
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if width := displayWidth(line); width != 30 {
+			t.Errorf("line %q is %d columns wide, expected 30", line, width)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package climenus
+
+import (
+	"strings"
+)
+
+// input for showing help for the current menu, or for a command with "help <command>"
+const helpInput = "help"
+
+// struct describing a command that is available in every menu shown by MenuLoop
+type builtinCommand struct {
+	name        string // input for the command
+	usage       string // usage shown in help
+	description string // description shown in help
+	paged       bool   // whether the command is only available in menus with a PageSize
+	prefix      bool   // whether the input for the command is name followed directly by text
+}
+
+// commands available in every menu shown by MenuLoop, in the order shown in help.
+// Commands registered on a menu with the same name take precedence over these.
+var builtinCommands = []builtinCommand{
+	{backInput, "back", "go back to the previous menu", false, false},
+	{homeInput, "home", "go back to the top level menu", false, false},
+	{gotoInput, "goto <path>", "go to a menu by its path from the top level menu, e.g. goto edit/2", false, false},
+	{helpInput, "help [command]", "show help for this menu, or for a command", false, false},
+	{nextPageInput, "next", "show the next page of commands", true, false},
+	{prevPageInput, "prev", "show the previous page of commands", true, false},
+	{pageInput, "page <number>", "show a page of commands by its number", true, false},
+	{filterInput, "/<text>", "show only commands containing text, / alone shows all commands", false, true},
+}
+
+// returns the built-in commands available in this menu, the paging commands
+// are only available if the menu has a PageSize
+func (menu *Menu) builtins() []builtinCommand {
+	builtins := make([]builtinCommand, 0, len(builtinCommands))
+	for _, builtin := range builtinCommands {
+		if !builtin.paged || menu.PageSize > 0 {
+			builtins = append(builtins, builtin)
+		}
+	}
+	return builtins
+}
+
+// returns the built-in command available in this menu with the given input,
+// or nil if there is none
+func (menu *Menu) findBuiltin(commandString string) *builtinCommand {
+	for _, builtin := range menu.builtins() {
+		if builtin.name == commandString || (builtin.prefix && strings.HasPrefix(commandString, builtin.name)) {
+			return &builtin
+		}
+	}
+	return nil
+}
+
+// returns true if the input is one of the built-in commands available in this menu
+func (menu *Menu) isBuiltinInput(commandString string) bool {
+	return menu.findBuiltin(commandString) != nil
+}
+
+// runs a built-in command for the current menu, returns false if the input
+// isn't one of them
+func (nav *navigator) runBuiltin(args []string) (bool, error) {
+	if !nav.current().isBuiltinInput(args[0]) {
+		return false, nil
+	}
+
+	if strings.HasPrefix(args[0], filterInput) {
+		nav.current().filterFromArgs(args)
+		return true, nil
+	}
+
+	switch args[0] {
+	case backInput:
+		nav.back()
+	case homeInput:
+		nav.home()
+	case gotoInput:
+		return true, nav.jump(strings.Join(args[1:], " "))
+	case helpInput:
+		return true, nav.current().showHelp(args[1:])
+	case nextPageInput:
+		return true, nav.current().NextPage()
+	case prevPageInput:
+		return true, nav.current().PrevPage()
+	case pageInput:
+		return true, nav.current().showPage(args[1:])
+	}
+
+	return true, nil
+}
@@ -1,11 +1,10 @@
 package climenus
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"math"
-	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -17,6 +16,14 @@ const FloatType = "float"
 const ExitProgram = "exit program command issued"
 const BackCommand = "back command issued"
 
+// error returned by an Execute function to exit the program, MenuLoop returns it
+// (or any error wrapping it) to its caller
+var ErrExitProgram = errors.New(ExitProgram)
+
+// error returned by an Execute function to go back to the previous menu,
+// matched by MenuLoop with errors.Is so it can be wrapped
+var ErrBack = errors.New(BackCommand)
+
 const optionNumberColIdx = 0
 const nameColIdx = 1
 const descriptionColIdx = 2
@@ -28,8 +35,34 @@ type Menu struct {
 	Columns     []MenuColumn        // slice with specifiers for the columns of this menu
 	// ColWidths []int // slice containing column widths for each column of the menu
 	// Labels []string // slice containing column labels for each column of the menu
-	Instructions string      // instructions to print when menu is reached
-	Data         interface{} // field for storing additional data that may need to be accessed by commands
+	Instructions string       // instructions to print when menu is reached
+	Data         interface{}  // field for storing additional data that may need to be accessed by commands
+	Session      *Session     // input/output streams for this menu, uses the default session (stdin/stdout) if nil
+	Theme        *Theme       // styles for this menu, uses the theme of the menu it was opened from if nil
+	Border       *BorderStyle // lines drawn around the columns, uses the border of the menu it was opened from if nil
+	Title        string       // label for this menu in breadcrumbs, defaults to the name of the command that opened it
+	// function called by MenuLoop before the menu is shown, used to rebuild
+	// commands of menus whose contents can change (e.g. menus listing stored data)
+	Refresh func(menu *Menu) error
+	// middleware run around the commands of this menu and the menus opened from it,
+	// inside the middleware of the menu it was opened from, see Middleware
+	Middleware []Middleware
+	// whether command names and aliases are matched ignoring case
+	CaseInsensitive bool
+	// whether a command can be selected by a prefix of its name or an alias,
+	// as long as the prefix matches only one command
+	MatchPrefixes bool
+	// whether pressing Enter after an unknown command with a single "did you mean"
+	// suggestion issues the suggested command
+	AcceptSuggestions bool
+	// max number of commands shown at once, the commands are split into pages
+	// shown with "next", "prev" and "page N" if set. All commands are shown if 0
+	PageSize int
+
+	page     int      // index of the page of commands shown when PageSize is set
+	filter   string   // text the commands shown are filtered by, set with "/text"
+	parent   *Menu    // menu this menu was opened from as a SubMenu, nil at the top level
+	openedBy *Command // command this menu was opened by as a SubMenu, nil at the top level
 }
 
 // add a new command to the menu, adds the command to the list of commands
@@ -38,34 +71,76 @@ func (menu *Menu) AddCommand(command *Command) {
 	if menu.CommandsMap == nil {
 		menu.CommandsMap = make(map[string]*Command, 1)
 	}
-	// add the command to the menu command list and map, then update its option number
+	// add the command to the menu command list and map, then update its option number.
+	// Commands without a name are only selected by their option number
 	menu.Commands = append(menu.Commands, command)
-	menu.CommandsMap[command.Name] = command
+	for _, name := range command.names() {
+		if name != "" {
+			menu.CommandsMap[name] = command
+		}
+	}
 	command.OptionNumber = len(menu.Commands)
 }
 
-// Prints the menu and shows the options for its commands
+// Prints the menu and shows the options for its commands.
+// Cells that can't be shown as their column's type are shown as text, see Validate
 func (menu *Menu) ShowMenu() error {
-	fmt.Println("\n\n" + menu.Instructions)
+	out := menu.Out()
+	theme := menu.theme()
+	fmt.Fprint(out, "\n\n")
+	// show where this menu is in the menu tree when it was opened as a SubMenu
+	if menu.parent != nil {
+		fmt.Fprintln(out, theme.Breadcrumbs.Render(menu.Breadcrumbs()))
+	}
+	fmt.Fprintln(out, menu.Instructions)
 	// if menu just presents instructions then can return here
 	if len(menu.Columns) == 0 {
 		return nil
 	}
+	if menu.filter != "" {
+		fmt.Fprintln(out, theme.Info.Render(fmt.Sprintf("filter: %q (enter %s to clear)", menu.filter, filterInput)))
+	}
 
+	// flexible columns are laid out for the terminal width at every render
+	columns := menu.layout()
+	border := menu.border()
 	totalWidth := 0
-	for _, col := range menu.Columns {
-		formatString, _ := col.typeFormatString()
-		fmt.Printf(formatString, col.ColWidth, col.Label)
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		// labels are always strings, whatever the type of the column contents
+		if col.Align == AlignCenter {
+			header[i] = centerText(col.Label, int(math.Abs(float64(col.ColWidth)))) + " "
+		} else {
+			header[i] = fmt.Sprintf("%*s ", printWidth(col.ColWidth, col.Label), col.Label)
+		}
 		totalWidth += int(math.Abs(float64(col.ColWidth)))
 	}
-	fmt.Print("\n")
+	if border != nil && border.Top {
+		printRule(out, border.rule(columns, border.TopLeft, border.TopJoin, border.TopRight), theme.Separator)
+	}
+	fmt.Fprintln(out, border.line(header, theme.Header, theme.Separator))
+	if border == nil {
+		fmt.Fprintln(out, theme.Separator.Render(separatorLine(totalWidth+5)))
+	} else {
+		printRule(out, border.rule(columns, border.MiddleLeft, border.MiddleJoin, border.MiddleRight), theme.Separator)
+	}
 
-	for i := 0; i < totalWidth+5; i++ {
-		fmt.Print("-")
+	commands := menu.pageCommands()
+	rowRules := border != nil && border.RowRules && hasMultiLineRows(columns, commands)
+	for i, command := range commands {
+		if rowRules && i > 0 {
+			printRule(out, border.rule(columns, border.MiddleLeft, border.MiddleJoin, border.MiddleRight), theme.Separator)
+		}
+		menu.renderRows(columns, command, theme.rowStyle(i), border)
+	}
+	if border != nil && border.Bottom {
+		printRule(out, border.rule(columns, border.BottomLeft, border.BottomJoin, border.BottomRight), theme.Separator)
 	}
-	fmt.Print("\n")
-	for _, command := range menu.Commands {
-		menu.renderCommand(command)
+	if len(menu.pageCommands()) == 0 && menu.filter != "" {
+		fmt.Fprintln(out, theme.Info.Render("no commands match the filter"))
+	}
+	if menu.PageCount() > 1 {
+		fmt.Fprintln(out, theme.Info.Render(menu.pageFooter()))
 	}
 
 	return nil
@@ -74,12 +149,19 @@ func (menu *Menu) ShowMenu() error {
 // Renders the text representing a command within the menu,
 // handles formatting of command data into columns using format strings
 // and splits lines that are too long for the column width into multiple rows
-// then prints the processed text to the console.
+// then prints the processed text to the menu's output.
 // Returns the resulting format strings and args used for Printf (for testing purposes)
 func (menu *Menu) renderCommand(command *Command) ([]string, [][]interface{}) {
+	return menu.renderRows(menu.layout(), command, menu.theme().Row, menu.border())
+}
+
+// Renders the text representing a command with the given columns, which have
+// the widths the menu's columns are laid out with, the rows in rowStyle
+// and the lines between the columns drawn with border
+func (menu *Menu) renderRows(columns []MenuColumn, command *Command, rowStyle Style, border *BorderStyle) ([]string, [][]interface{}) {
 
 	// get splits for the command, breaking up column context into rows by column width
-	splits, height := getSplits(&menu.Columns, command)
+	splits, height := getSplits(&columns, command)
 
 	// pad shorter splits with empty strings to match max height column
 	padWithEmptyStrings(&splits, height)
@@ -87,17 +169,32 @@ func (menu *Menu) renderCommand(command *Command) ([]string, [][]interface{}) {
 	// put together the args for the format string for each column in a []interface{}
 	// (as a pair of column width, column contents for each column)
 	// so that these can be used to supply the column widths and content to Printf
-	fstringArgs := combineFstringArgs(&menu.Columns, &splits, height)
+	fstringArgs := combineFstringArgs(&columns, &splits, height)
 
 	// get the format strings for each column to use for Printf
-	formatStrings, err := getFormatStrings(&menu.Columns)
-	if err != nil {
-		return nil, nil
-	}
+	formatStrings := getFormatStrings(&columns)
 
-	// use the formatStrings and the args to render the text with Printf
+	// use the formatStrings and the args to render the text with Printf,
+	// a cell at a time so cells can be styled
+	optionNumberStyle := rowStyle.Add(menu.theme().OptionNumber)
 	for row := range height {
-		fmt.Printf(strings.Join(formatStrings, "")+"\n", fstringArgs[row]...)
+		rowFormats := rowFormatStrings(formatStrings, fstringArgs[row])
+		args := printArgs(fstringArgs[row])
+		cells := make([]string, len(rowFormats))
+		for col, format := range rowFormats {
+			cell := fmt.Sprintf(format, args[2*col], args[2*col+1])
+			if columns[col].Align == AlignCenter {
+				// format without the width, then pad on both sides
+				text := strings.TrimSuffix(fmt.Sprintf(strings.Replace(format, "*", "", 1), args[2*col+1]), " ")
+				cell = centerText(text, int(math.Abs(float64(columns[col].ColWidth)))) + " "
+			}
+			if col == optionNumberColIdx && row == 0 {
+				cells[col] = optionNumberStyle.Render(cell)
+			} else {
+				cells[col] = rowStyle.Render(cell)
+			}
+		}
+		fmt.Fprintln(menu.Out(), border.line(cells, "", menu.theme().Separator))
 	}
 
 	return formatStrings, fstringArgs
@@ -105,61 +202,38 @@ func (menu *Menu) renderCommand(command *Command) ([]string, [][]interface{}) {
 
 // Takes the contents of the command data to be printed,
 // and splits it into multiple rows if the data for that column is longer than
-// the column width. Returns a slice of slices of strings representing these splits
+// the column width. Returns a slice of slices of cell values representing these splits
 // as well as an int representing the height in rows needed for this command.
-func getSplits(columns *[]MenuColumn, command *Command) ([][]string, int) {
-	// make an empty list of lists of strings for each column
+// Cells of int and float columns are kept as numbers on a single row, any other
+// contents are split into strings.
+func getSplits(columns *[]MenuColumn, command *Command) ([][]interface{}, int) {
+	// make an empty list of lists of cells for each column
 	// after the contents of that columnn are split into rows
 	height := 0
 	n := len(*columns)
-	// formatStrings := make([][]string, n)
-
-	optionNumber := strconv.Itoa(command.OptionNumber)
-	contentsOfDefaultColumns := map[int]string{
-		optionNumberColIdx: optionNumber,
-		nameColIdx:         command.Name,
-		descriptionColIdx:  command.Description,
-	}
 
-	// TODO: add support for AdditionalColumns
-
-	splits := make([][]string, n)
-	// loop through each column and generate a list of strings for that column
+	splits := make([][]interface{}, n)
+	// loop through each column and generate a list of cells for that column
 	// split into separate rows of max length colWidth[i]
 	for i := 0; i < n; i++ {
-		// for width get abs value since formatting can use negatives to indicate left justify
-		width := int(math.Abs(float64((*columns)[i].ColWidth)))
-		s := contentsOfDefaultColumns[i]
-		// break up the string into words to build splits one word at a time
-		// (prevents breaking up a word in the middle when splitting lines)
-		words := strings.Split(strings.TrimSpace(s), " ")
-
-		// empty slice to use for splits for this column
-		splits[i] = make([]string, 0)
-
-		currentWidth := 0
-		var sb strings.Builder
-		for wordNum, word := range words {
-			// if the word would exceed allowable width, then add current sb.String() as a split
-			// and start a new one
-			if len(word)+currentWidth > width {
-				splits[i] = append(splits[i], sb.String()[:len(sb.String())-1])
-				sb.Reset()
-				currentWidth = 0
-			}
-			// after checking if split is needed, write current word to sb
-			sb.WriteString(word)
-			currentWidth += len(word)
-			// if this isn't the last word, add a space
-			if wordNum < len(words)-1 {
-				sb.WriteString(" ")
-				currentWidth += 1
+		column := (*columns)[i]
+		contents := command.columnContents(i)
+
+		// typed values are never split, they are passed through as a single cell
+		if column.Type != StringType {
+			value, ok := column.typedValue(contents)
+			if ok {
+				splits[i] = []interface{}{column.displayValue(value)}
+				height = max(height, 1)
+				continue
 			}
+		}
 
-			// if it is the last word, then just add sb so far as a new split
-			if wordNum == len(words)-1 {
-				splits[i] = append(splits[i], sb.String())
-			}
+		// for width get abs value since formatting can use negatives to indicate left justify
+		width := int(math.Abs(float64(column.ColWidth)))
+		splits[i] = make([]interface{}, 0)
+		for _, split := range splitWords(fmt.Sprint(contents), width) {
+			splits[i] = append(splits[i], split)
 		}
 
 		// height for the whole row should be the height of the max len split for the row
@@ -169,27 +243,119 @@ func getSplits(columns *[]MenuColumn, command *Command) ([][]string, int) {
 	return splits, height
 }
 
+// Splits a string into rows no wider than width, breaking it up between words
+// (prevents breaking up a word in the middle when splitting lines). Widths are measured
+// in terminal columns, words wider than width are broken up, tabs are expanded
+// and newlines always start a new row
+func splitWords(s string, width int) []string {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(s), "\r\n", "\n"), "\n")
+
+	splits := make([]string, 0)
+	for _, line := range lines {
+		splits = append(splits, wrapLine(expandTabs(strings.TrimRight(line, " ")), width)...)
+	}
+
+	return splits
+}
+
+// Splits a line without newlines into rows no wider than width, breaking it up between
+// words, and breaking up words that are wider than width
+func wrapLine(line string, width int) []string {
+	// break up the line into words to build splits one word at a time
+	words := strings.Split(line, " ")
+
+	splits := make([]string, 0)
+
+	currentWidth := 0
+	// whether a word has been written to the current split
+	started := false
+	var sb strings.Builder
+	for _, word := range words {
+		wordWidth := displayWidth(word)
+		// if the word (and the space before it) would exceed allowable width,
+		// then add current sb.String() as a split and start a new one
+		if started && currentWidth+1+wordWidth > width {
+			splits = append(splits, sb.String())
+			sb.Reset()
+			currentWidth = 0
+			started = false
+		}
+
+		// words too wide for a row of their own are broken up over several rows,
+		// and the last piece starts the next split
+		if wordWidth > width {
+			pieces := breakWord(word, width)
+			splits = append(splits, pieces[:len(pieces)-1]...)
+			word = pieces[len(pieces)-1]
+			wordWidth = displayWidth(word)
+		}
+
+		// after checking if split is needed, write current word to sb
+		if started {
+			sb.WriteString(" ")
+			currentWidth += 1
+		}
+		sb.WriteString(word)
+		currentWidth += wordWidth
+		started = true
+	}
+
+	// add the rest of the line as the last split
+	return append(splits, sb.String())
+}
+
 // Gets format strings (e.g. "%*s ", "%*d ") to use in the Printf call for rendering.
-// Computes these by using the column type for each column of the menu.
+// Computes these by using the column type for each column of the menu,
+// columns with an invalid type are shown as strings.
 // Returns the list of format strings.
-func getFormatStrings(columns *[]MenuColumn) ([]string, error) {
+func getFormatStrings(columns *[]MenuColumn) []string {
 	n := len(*columns)
 	formatStrings := make([]string, n)
 
 	for i := 0; i < n; i++ {
 		typeString, err := (*columns)[i].typeFormatString()
 		if err != nil {
-			return nil, err
+			typeString = "%*s "
 		}
 		formatStrings[i] = typeString
 	}
 
-	return formatStrings, nil
+	return formatStrings
+}
+
+// Gets the format strings to use for a single row of a command's text.
+// Cells holding strings in int or float columns (padding rows, or values that
+// couldn't be converted to the column type) are printed with a string verb instead.
+func rowFormatStrings(formatStrings []string, rowArgs []interface{}) []string {
+	rowFormats := make([]string, len(formatStrings))
+	for i, formatString := range formatStrings {
+		rowFormats[i] = formatString
+		// args are pairs of column width, column contents
+		if _, isString := rowArgs[2*i+1].(string); isString {
+			rowFormats[i] = "%*s "
+		}
+	}
+
+	return rowFormats
+}
+
+// Returns a copy of the args for a row with the widths of string cells adjusted,
+// so cells are padded by their display width rather than their number of runes
+func printArgs(rowArgs []interface{}) []interface{} {
+	args := slices.Clone(rowArgs)
+	// args are pairs of column width, column contents
+	for i := 0; i+1 < len(args); i += 2 {
+		if cell, isString := args[i+1].(string); isString {
+			args[i] = printWidth(args[i].(int), cell)
+		}
+	}
+
+	return args
 }
 
 // Pads splits in place with empty strings, based on the height in rows needed
 // for this command (i.e. the height of the largest column for this command).
-func padWithEmptyStrings(splits *[][]string, height int) {
+func padWithEmptyStrings(splits *[][]interface{}, height int) {
 	n := len(*splits)
 	// For any columns that have fewer splits than the other columns
 	// need to add empty strings at the end
@@ -204,7 +370,7 @@ func padWithEmptyStrings(splits *[][]string, height int) {
 // to display in the column, by putting these into a slice of slices of interface{}
 // so that each index in the slice of slices can be used with the ... operator
 // to function as the additional arguments in the Printf call for rendering.
-func combineFstringArgs(columns *[]MenuColumn, splits *[][]string, height int) [][]interface{} {
+func combineFstringArgs(columns *[]MenuColumn, splits *[][]interface{}, height int) [][]interface{} {
 	n := len(*columns)
 	// put together the args for the format string for each column in a []interface{}
 	// so that these can be used to supply the column widths and content
@@ -232,7 +398,9 @@ func (menu *Menu) CommandByOptionNumber(optionNumber int) (*Command, error) {
 	return menu.Commands[index], nil
 }
 
-// look up a command from the option number or name
+// look up a command from the option number, name or alias. Names are also
+// matched ignoring case and by unique prefix if the menu has those modes enabled,
+// an *AmbiguousCommandError is returned if a prefix matches more than one command.
 // note if there is only one possible command for this menu, then
 // just return that command
 func (menu *Menu) Command(commandString string) (*Command, error) {
@@ -246,107 +414,267 @@ func (menu *Menu) Command(commandString string) (*Command, error) {
 		return menu.CommandByOptionNumber(optionNumber)
 	}
 
-	// otherwise get command from the map
-	command, isValidCommand := menu.CommandsMap[commandString]
-	if !isValidCommand {
-		return nil, errors.New("invalid command")
-	}
-
-	return command, nil
-
+	// otherwise look the command up by name
+	return menu.lookupCommand(commandString)
 }
 
 // a default validator to use for menu commands
 // checks if the provided input matches a valid command name,
 // or if it matches a valid command number.
-func (menu *Menu) commandValidator(commandString string) (bool, error) {
-	if commandString == "back" {
-		return true, nil
+func (menu *Menu) commandValidator(input string) (bool, error) {
+	words, err := Tokenize(input)
+	if err != nil {
+		return false, err
 	}
-	// strings := strings.Split(commandString, " ")
-	// if len(strings) > 1 {
-	// 	return false, errors.New("this command does not support additional arguments")
-	// }
-	// command := strings[0]
+	// empty input isn't a command, so the prompt is just shown again
+	if len(words) == 0 {
+		return false, nil
+	}
+	commandString := words[0]
 	optionNumber, err := strconv.Atoi(commandString)
 	if err != nil {
-		// see if input s is in CommandsMap for non-numeric
-		_, isValid := menu.CommandsMap[commandString]
-		if !isValid {
-			return false, errors.New("not a valid command")
+		// see if input s names a command for non-numeric
+		command, lookupErr := menu.lookupCommand(commandString)
+		if lookupErr != nil && menu.isBuiltinInput(commandString) {
+			return true, nil
 		}
-		return true, nil
+		var ambiguousErr *AmbiguousCommandError
+		if errors.As(lookupErr, &ambiguousErr) {
+			return false, lookupErr
+		}
+		if lookupErr != nil {
+			return false, menu.unknownCommandError(commandString)
+		}
+		err = command.validateArgs(words)
+		return err == nil, err
 	}
 	// for numeric check if index is valid
 	isValid := (optionNumber > 0 && optionNumber <= len(menu.Commands))
 	if !isValid {
 		return false, errors.New("optionNumber is outside valid range")
 	}
-	return true, nil
+	err = menu.Commands[optionNumber-1].validateArgs(words)
+	return err == nil, err
 }
 
+// Execute function for a command that goes back to the previous menu
 func BackFunc(args []string, menu *Menu) error {
-	return errors.New(BackCommand)
+	return ErrBack
 }
 
+// Execute function for a command that exits the program
 func ExitFunc(args []string, menu *Menu) error {
-	return errors.New(ExitProgram)
+	return ErrExitProgram
 }
 
-// main loop for a CLI menu, takes user input until a valid command
-// is issued or user elects to go back or exit the program
+// main loop for a CLI menu, takes user input until the user elects
+// to go back from this menu or exit the program.
+// Issuing a command with a SubMenu enters that submenu, after running the command's
+// Execute function if it has one. The loop keeps a stack of the submenus entered
+// and supports "back" (previous menu), "home" (this menu, the top level menu of the
+// loop) and "goto <path>" to navigate the tree. Goto paths are resolved from the top
+// level menu whichever submenu they are entered in, e.g. "goto edit/2",
+// and "help" to show help for the menu or one of its commands.
+// Execute functions can navigate by returning ErrBack, GoBack or GoHome, and exit
+// with ErrExitProgram, which is returned. If GoBack asks for more levels than
+// this loop has open, the rest are returned as a GoBack error for the caller.
+// If the menu's session runs a script, the loop stops at the first input that isn't
+// accepted, see RunScript. Called from a command of a MenuLoopContext, it stops
+// with the same context. Interrupts go back a menu once signals are trapped, see
+// Session.TrapSignals. Commands are run through the middleware added with Use and
+// set on the menus, see Middleware.
 func (menu *Menu) MenuLoop() error {
+	return menu.MenuLoopContext(menu.session().context())
+}
 
-	commandString := ""
+// runs the menu loop with the session's context, see MenuLoopContext
+func (menu *Menu) menuLoop() error {
+	nav := navigator{stack: []*Menu{menu}}
+	defer nav.truncate(0)
+	session := menu.session()
+
+	for !nav.done() {
+		if err := session.context().Err(); err != nil {
+			return err
+		}
+		current := nav.current()
+		err := current.refresh()
+		if err != nil {
+			current.printError(err)
+		}
 
-	for commandString != "back" && commandString != "exit" {
-		menu.ShowMenu()
-		// prompts := []string{""}
-		// validators := []func(string, []string) (bool, error){menu.commandValidator}
-		input := UserInput("", menu.commandValidator)
-		// inputStrings := strings.Split(input, " ")
-		args := strings.Split(input, " ")
-		// commandString = inputStrings[0]
-		commandString = args[0]
-		command, err := menu.Command(commandString)
-		// args := inputStrings[1:]
+		// a menu that can't be shown is a mistake in building it, so the loop stops
+		err = current.ShowMenu()
+		if err != nil {
+			nav.truncate(0)
+			return err
+		}
+		input, err := current.readCommandInput()
+		if errors.Is(err, ErrScriptEnded) {
+			// the script ended where it may, while waiting for a command
+			return nil
+		} else if errors.Is(err, ErrEndOfInput) && session.OnEOF == EOFBack {
+			nav.back()
+			continue
+		} else if errors.Is(err, ErrEndOfInput) {
+			return session.OnEOF.stopError(err)
+		} else if errors.Is(err, ErrInterrupted) && len(nav.stack) > 1 {
+			nav.back()
+			continue
+		} else if errors.Is(err, ErrInterrupted) && !current.confirmExit() {
+			continue
+		} else if errors.Is(err, ErrInterrupted) || errors.Is(err, ErrTerminated) {
+			return session.exitOnSignal(err)
+		} else if err != nil {
+			return err
+		}
+		args, err := Tokenize(input)
+		if err == nil && len(args) == 0 {
+			continue
+		}
+		if err != nil {
+			current.printError(err)
+			if session.Script {
+				return session.scriptError("", input, err)
+			}
+			continue
+		}
+		commandString := args[0]
+		command, err := current.Command(commandString)
 
 		if err != nil {
-			fmt.Println(err.Error())
-		} else {
-			err := command.Execute(args, menu)
-			if err != nil && err.Error() == ExitProgram {
-				return err
-			} else if err != nil && err.Error() == BackCommand {
-				return nil
-			} else if err != nil {
-				// fmt.Println("debug1")
-				fmt.Println(err.Error())
+			isBuiltin, builtinErr := nav.runBuiltin(args)
+			if builtinErr != nil {
+				current.printError(builtinErr)
+				if session.Script {
+					return session.scriptError("", input, builtinErr)
+				}
+			} else if !isBuiltin {
+				current.printError(err)
+				if session.Script {
+					return session.scriptError("", input, err)
+				}
+			}
+			continue
+		}
+
+		// commands that only open a SubMenu are run too, so middleware can stop them
+		called, err := current.runCommand(command, args)
+		var navErr *NavigationError
+		var stop *loopStop
+		if errors.As(err, &stop) {
+			return stop.err
+		} else if errors.Is(err, ErrExitProgram) {
+			nav.truncate(0)
+			return err
+		} else if errors.As(err, &navErr) {
+			// levels left over after leaving this loop are passed on to
+			// the menu loop this one was called from
+			if levels := navErr.apply(&nav); levels > 0 {
+				return GoBack(levels)
+			}
+			continue
+		} else if errors.Is(err, ErrBack) {
+			nav.back()
+			continue
+		} else if err != nil {
+			// fmt.Println("debug1")
+			current.printError(err)
+			if session.Script {
+				return session.scriptError("", input, err)
 			}
+			continue
+		}
+
+		// the SubMenu isn't opened if middleware stopped the command without an error
+		if command.SubMenu != nil && called {
+			nav.enter(command)
 		}
 	}
 
-	// else command was "back", so just return
 	return nil
 }
 
 // Specifier for a MenuColumn with header label, width, and type
 type MenuColumn struct {
-	ColWidth int    // print width for this column
-	Type     string // type of this column (string, int, float)
+	ColWidth int    // print width for this column, negative to left justify
+	Type     string // type of this column (string, int, float, bool, duration, time, percent)
 	Label    string // label to print for this column header
+	// min print width for a column that grows to fill the terminal width left by
+	// the other columns. Columns with a MinWidth or Fraction are laid out for the
+	// terminal each time the menu is shown, ignoring the size of ColWidth
+	MinWidth int
+	// fraction (e.g. 0.5) of the terminal width left by fixed width columns
+	// to give this column
+	Fraction float64
+	// alignment of the column's contents, overriding the sign of ColWidth if set
+	Align Alignment
+	// number of decimal places shown by float and percent columns,
+	// 0 for the default (6 for float, none for percent) or NoDecimals
+	Precision int
+	// how bool and time columns are shown: the text for true and false separated
+	// by a slash for bool (default "✓/✗"), or a time layout for time
+	// (default "2006-01-02 15:04")
+	Format string
+}
+
+// converts a cell value to the type of this column, so int columns get an int
+// and float columns get a float64. Strings are parsed as numbers when needed.
+// Returns false if the value can't be represented as the column type.
+func (c *MenuColumn) typedValue(value interface{}) (interface{}, bool) {
+	return convertToType(c.Type, value)
+}
+
+// converts a value to a type used by climenus (string, int, float, and the types
+// handled by convertToAddedType), so int gives an int and float gives a float64.
+// Strings are parsed as numbers when needed.
+// Returns false if the value can't be represented as the type.
+func convertToType(typeName string, value interface{}) (interface{}, bool) {
+	switch typeName {
+	case IntType:
+		switch v := value.(type) {
+		case int:
+			return v, true
+		case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			n, err := strconv.Atoi(fmt.Sprint(v))
+			return n, err == nil
+		case string:
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			return n, err == nil
+		}
+	case FloatType:
+		switch v := value.(type) {
+		case float64:
+			return v, true
+		case float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			f, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+			return f, err == nil
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			return f, err == nil
+		}
+	case StringType:
+		return fmt.Sprint(value), true
+	}
+
+	return convertToAddedType(typeName, value)
 }
 
 // returns the format string to use for the column type
 func (c *MenuColumn) typeFormatString() (string, error) {
 	fmtStr := ""
-	if c.Type == "string" {
+	switch c.Type {
+	case StringType, BoolType, DurationType, TimeType, PercentType:
+		// the added types are formatted as strings by displayValue
 		fmtStr = "%*s "
-	} else if c.Type == "int" {
+	case IntType:
 		fmtStr = "%*d "
-	} else if c.Type == "float" {
+	case FloatType:
 		fmtStr = "%*f "
-	} else {
+		if c.Precision != 0 {
+			fmtStr = "%*." + strconv.Itoa(c.decimals()) + "f "
+		}
+	default:
 		return "", errors.New("invalid column type")
 	}
 
@@ -359,50 +687,78 @@ type Command struct {
 	OptionNumber int
 	// name of the command, can be used to select this command from menu
 	Name string
+	// other names that can be used to select this command from menu,
+	// registered in CommandsMap by AddCommand
+	Aliases []string
 	// longer description of the command
 	Description string
-	// additional columns to print in the menu for this command, if needed
+	// additional columns to print in the menu for this command, if needed.
+	// Values are shown in the menu columns after option number, name and description,
+	// and are converted to the type of their column (e.g. "3" for an "int" column)
 	AdditionalColumns []string
-	// function to execute when this command is issued
+	// typed values for the additional columns, used instead of AdditionalColumns for
+	// any column where the value isn't nil. Values should match the type of their
+	// column (e.g. an int for an "int" column)
+	Cells []interface{}
+	// function to execute when this command is issued, args holds the input split
+	// into words with the command as entered in args[0]
 	Execute func(args []string, menu *Menu) error
-	// SubMenu that should be displayed when this command is issued
+	// function to execute when this command is issued, used instead of Execute when set.
+	// The arguments given are validated against ArgSpecs and converted before Run is called
+	Run func(args *Args, menu *Menu) error
+	// specifiers for the arguments taken by the command when it uses Run
+	ArgSpecs []ArgSpec
+	// long-form help text for the command, shown by "help <command>"
+	Help string
+	// example inputs for the command, shown by "help <command>"
+	Examples []string
+	// SubMenu that should be displayed when this command is issued,
+	// entered by MenuLoop after Execute (if set) runs without error
 	SubMenu *Menu
 }
 
-func UserInput(prompt string, validator func(string) (bool, error)) string {
+// returns the contents of the column at colIdx for this command, before any splitting.
+// The first columns hold the option number, name and description, the rest
+// come from Cells or AdditionalColumns (empty if the command has no value for the column)
+func (command *Command) columnContents(colIdx int) interface{} {
+	switch colIdx {
+	case optionNumberColIdx:
+		return command.OptionNumber
+	case nameColIdx:
+		return command.Name
+	case descriptionColIdx:
+		return command.Description
+	}
 
-	scanner := bufio.NewScanner(os.Stdin)
-
-	isValid := false
-	err := error(nil)
-	input := ""
-	// inputSlice := make([]string, 0)
-	for !isValid {
-		fmt.Println(prompt)
-		scanner.Scan()
-		input = strings.TrimSpace(scanner.Text())
-		// inputSlice = strings.Split(input, " ")
-		// commandString := inputSlice[0]
-		// args := inputSlice[1:]
-		isValid, err = validator(input)
-		if err != nil {
-			fmt.Println(err.Error())
-		}
+	additionalIdx := colIdx - descriptionColIdx - 1
+	if additionalIdx < len(command.Cells) && command.Cells[additionalIdx] != nil {
+		return command.Cells[additionalIdx]
 	}
+	if additionalIdx < len(command.AdditionalColumns) {
+		return command.AdditionalColumns[additionalIdx]
+	}
+	return ""
+}
 
-	return input
+// returns the number of additional columns the command has values for
+func (command *Command) additionalColumnCount() int {
+	return max(len(command.Cells), len(command.AdditionalColumns))
 }
 
-func UserInputLoop(prompt string, exitLoop string, validator func(string) (bool, error)) []string {
-	input := ""
-	inputStrings := make([]string, 0)
+// prints the prompt and reads input from the default session (stdin/stdout unless
+// changed with SetDefaultSession) until the validator accepts it
+func UserInput(prompt string, validator func(string) (bool, error)) string {
+	return defaultSession.UserInput(prompt, validator)
+}
 
-	for input != exitLoop {
-		input = UserInput(prompt, validator)
-		if input != exitLoop {
-			inputStrings = append(inputStrings, input)
-		}
-	}
+// takes validated input like UserInput from the default session, returning the
+// error if input ends or is stopped first, see Session.UserInputErr
+func UserInputErr(prompt string, validator func(string) (bool, error)) (string, error) {
+	return defaultSession.UserInputErr(prompt, validator)
+}
 
-	return inputStrings
+// takes validated input from the default session until exitLoop is entered,
+// returning the inputs entered before it
+func UserInputLoop(prompt string, exitLoop string, validator func(string) (bool, error)) []string {
+	return defaultSession.UserInputLoop(prompt, exitLoop, validator)
 }
@@ -2,9 +2,30 @@ package climenus
 
 import (
 	"fmt"
+	"io"
+	"slices"
+	"strings"
 	"testing"
 )
 
+// columns of the menus made by newTestMenu, an option number and a name
+var testMenuColumns = []MenuColumn{
+	{ColWidth: 2, Type: IntType, Label: "#"},
+	{ColWidth: -5, Type: StringType, Label: "Name"},
+}
+
+// returns a menu with the testMenuColumns and a command for each of names, reading
+// input from input and writing its output to out. Used by the tests of every file,
+// which add the commands and settings they need
+func newTestMenu(input string, out io.Writer, names ...string) *Menu {
+	menu := &Menu{Columns: slices.Clone(testMenuColumns)}
+	menu.Session = NewSession(strings.NewReader(input), out)
+	for _, name := range names {
+		menu.AddCommand(&Command{Name: name})
+	}
+	return menu
+}
+
 var dummyMenus = []struct {
 	description string
 	name        string
@@ -338,3 +359,84 @@ func TestRenderCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderAdditionalColumns(t *testing.T) {
+	testCases := []struct {
+		name              string
+		additionalColumns []string
+		cells             []interface{}
+		expectedArgs      [][]interface{}
+	}{
+		{
+			name:  "testTypedCells",
+			cells: []interface{}{4, 2.5, "extra"},
+			expectedArgs: [][]interface{}{
+				{2, 3, 5, "test", 3, "", 5, 4, 6, 2.5, -10, "extra"},
+			},
+		},
+		{
+			name:              "testConvertedCells",
+			additionalColumns: []string{"7", "0.5", "wrap this extra column"},
+			expectedArgs: [][]interface{}{
+				{2, 3, 5, "test", 3, "", 5, 7, 6, 0.5, -10, "wrap this"},
+				{2, "", 5, "", 3, "", 5, "", 6, "", -10, "extra"},
+				{2, "", 5, "", 3, "", 5, "", 6, "", -10, "column"},
+			},
+		},
+		{
+			name:              "testMissingAndInvalidCells",
+			additionalColumns: []string{"seven"},
+			expectedArgs: [][]interface{}{
+				{2, 3, 5, "test", 3, "", 5, "seven", 6, "", -10, ""},
+			},
+		},
+		{
+			name:              "testCellsOverText",
+			additionalColumns: []string{"7", "0.5", "text"},
+			cells:             []interface{}{nil, float32(1.5)},
+			expectedArgs: [][]interface{}{
+				{2, 3, 5, "test", 3, "", 5, 7, 6, 1.5, -10, "text"},
+			},
+		},
+	}
+
+	expectedFormatStrings := []string{"%*d ", "%*s ", "%*s ", "%*d ", "%*f ", "%*s "}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var menu Menu
+			menu.Session = NewSession(nil, io.Discard)
+			menu.Columns = []MenuColumn{
+				{ColWidth: 2, Type: IntType, Label: "#"},
+				{ColWidth: 5, Type: StringType, Label: "Name"},
+				{ColWidth: 3, Type: StringType, Label: ""},
+				{ColWidth: 5, Type: IntType, Label: "Qty"},
+				{ColWidth: 6, Type: FloatType, Label: "Amount"},
+				{ColWidth: -10, Type: StringType, Label: "Extra"},
+			}
+
+			command := Command{
+				OptionNumber: 3, Name: "test", AdditionalColumns: tc.additionalColumns, Cells: tc.cells,
+			}
+
+			formatStrings, fstringArgs := menu.renderCommand(&command)
+
+			if len(fstringArgs) != len(tc.expectedArgs) {
+				t.Fatalf("got %v rows, expected %v", len(fstringArgs), len(tc.expectedArgs))
+			}
+			for i := range len(fstringArgs) {
+				for j := range len(fstringArgs[i]) {
+					if fstringArgs[i][j] != tc.expectedArgs[i][j] {
+						t.Errorf("row %v: got %v, expected %v", i, fstringArgs[i][j], tc.expectedArgs[i][j])
+					}
+				}
+			}
+
+			for i := range len(formatStrings) {
+				if formatStrings[i] != expectedFormatStrings[i] {
+					t.Errorf("got %v, expected %v", formatStrings[i], expectedFormatStrings[i])
+				}
+			}
+		})
+	}
+}
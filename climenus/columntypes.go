@@ -0,0 +1,214 @@
+package climenus
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const BoolType = "bool"         // column of true/false values, shown as check marks
+const DurationType = "duration" // column of time.Duration values
+const TimeType = "time"         // column of time.Time values, shown as a date and time
+const PercentType = "percent"   // column of fractions (0.25), shown as percentages (25%)
+
+// default Format for bool columns, the text for true and false separated by a slash
+const defaultBoolFormat = "✓/✗"
+
+// default Format for time columns, as a time layout
+const defaultTimeFormat = "2006-01-02 15:04"
+
+// layouts that strings are parsed with for time columns and arguments
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// Precision for float and percent columns that shows no decimal places
+const NoDecimals = -1
+
+// default number of decimal places shown by float columns
+const defaultFloatPrecision = 6
+
+// horizontal alignment of the contents of a column
+type Alignment int
+
+const (
+	AlignDefault Alignment = iota // aligned by the sign of ColWidth, right unless negative
+	AlignLeft
+	AlignRight
+	AlignCenter
+)
+
+// adds a column to the menu, returns an error without adding it if it isn't valid
+func (menu *Menu) AddColumn(column MenuColumn) error {
+	err := column.validate()
+	if err != nil {
+		return err
+	}
+	menu.Columns = append(menu.Columns, column)
+	return nil
+}
+
+// checks the menu's columns, and that the contents of its commands can be shown in them.
+// Returns an error describing the first problem found. Call it once the menu is built,
+// since ShowMenu shows cells that don't match their column's type as text
+func (menu *Menu) Validate() error {
+	for i := range menu.Columns {
+		err := menu.Columns[i].validate()
+		if err != nil {
+			return fmt.Errorf("column %d: %w", i+1, err)
+		}
+	}
+
+	for _, command := range menu.Commands {
+		for i := range menu.Columns {
+			column := &menu.Columns[i]
+			contents := command.columnContents(i)
+			// empty cells are allowed in every type of column
+			if contents == "" {
+				continue
+			}
+			if _, ok := column.typedValue(contents); !ok {
+				return fmt.Errorf("command %d: invalid value %q for column %q, must be of type %s",
+					command.OptionNumber, fmt.Sprint(contents), column.Label, column.Type)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checks the column's type, alignment, precision and format
+func (c *MenuColumn) validate() error {
+	_, err := c.typeFormatString()
+	if err != nil {
+		return fmt.Errorf("%w %q", err, c.Type)
+	}
+	if c.Align < AlignDefault || c.Align > AlignCenter {
+		return fmt.Errorf("invalid alignment %d", c.Align)
+	}
+	if c.Precision < NoDecimals {
+		return fmt.Errorf("invalid precision %d", c.Precision)
+	}
+	if c.Type == BoolType && c.Format != "" && strings.Count(c.Format, "/") != 1 {
+		return fmt.Errorf("invalid bool format %q, must be true and false text separated by /", c.Format)
+	}
+	if c.Format != "" && c.Type != BoolType && c.Type != TimeType {
+		return fmt.Errorf("format is only used for %s and %s columns", BoolType, TimeType)
+	}
+	return nil
+}
+
+// returns the number of decimal places shown by a float or percent column
+func (c *MenuColumn) decimals() int {
+	switch {
+	case c.Precision == NoDecimals:
+		return 0
+	case c.Precision > 0:
+		return c.Precision
+	case c.Type == FloatType:
+		return defaultFloatPrecision
+	}
+	return 0
+}
+
+// returns a typed cell value as it is shown in the column. Int and float values are
+// kept as numbers for Printf, values of the other types are formatted as strings
+func (c *MenuColumn) displayValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bool:
+		format := c.Format
+		if format == "" {
+			format = defaultBoolFormat
+		}
+		trueText, falseText, _ := strings.Cut(format, "/")
+		if v {
+			return trueText
+		}
+		return falseText
+	case time.Duration:
+		return v.String()
+	case time.Time:
+		format := c.Format
+		if format == "" {
+			format = defaultTimeFormat
+		}
+		return v.Format(format)
+	}
+
+	if c.Type == PercentType {
+		return strconv.FormatFloat(value.(float64)*100, 'f', c.decimals(), 64) + "%"
+	}
+	return value
+}
+
+// converts a value to one of the added types: bool to bool, duration to time.Duration,
+// time to time.Time and percent to a float64 fraction. Strings are parsed when needed,
+// percentages may be given with a % sign ("25%" gives 0.25).
+func convertToAddedType(typeName string, value interface{}) (interface{}, bool) {
+	s, isString := value.(string)
+	s = strings.TrimSpace(s)
+
+	switch typeName {
+	case BoolType:
+		if v, ok := value.(bool); ok {
+			return v, true
+		}
+		b, err := strconv.ParseBool(s)
+		return b, isString && err == nil
+	case DurationType:
+		if v, ok := value.(time.Duration); ok {
+			return v, true
+		}
+		d, err := time.ParseDuration(s)
+		return d, isString && err == nil
+	case TimeType:
+		if v, ok := value.(time.Time); ok {
+			return v, true
+		}
+		if !isString {
+			return nil, false
+		}
+		for _, layout := range timeLayouts {
+			t, err := time.Parse(layout, s)
+			if err == nil {
+				return t, true
+			}
+		}
+	case PercentType:
+		if percent, hasSign := strings.CutSuffix(s, "%"); isString && hasSign {
+			f, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+			return f / 100, err == nil
+		}
+		return convertToType(FloatType, value)
+	}
+
+	return nil, false
+}
+
+// returns text padded with spaces on both sides to width terminal columns,
+// with any odd space on the right
+func centerText(text string, width int) string {
+	padding := width - displayWidth(text)
+	if padding <= 0 {
+		return text
+	}
+	return strings.Repeat(" ", padding/2) + text + strings.Repeat(" ", padding-padding/2)
+}
+
+// returns the columns with ColWidth signed for their Align, negative for left
+// aligned columns. Centered columns keep the sign of ColWidth, and are padded
+// when they're rendered
+func alignColumns(columns []MenuColumn) []MenuColumn {
+	aligned := make([]MenuColumn, len(columns))
+	copy(aligned, columns)
+	for i := range aligned {
+		width := int(math.Abs(float64(aligned[i].ColWidth)))
+		switch aligned[i].Align {
+		case AlignLeft:
+			aligned[i].ColWidth = -width
+		case AlignRight:
+			aligned[i].ColWidth = width
+		}
+	}
+	return aligned
+}
@@ -0,0 +1,156 @@
+package climenus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestColumnDisplayValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		column   MenuColumn
+		value    interface{}
+		expected interface{}
+	}{
+		{name: "testBoolTrue", column: MenuColumn{Type: BoolType}, value: true, expected: "✓"},
+		{name: "testBoolFalseString", column: MenuColumn{Type: BoolType}, value: "false", expected: "✗"},
+		{name: "testBoolFormat", column: MenuColumn{Type: BoolType, Format: "yes/no"}, value: true, expected: "yes"},
+		{name: "testDuration", column: MenuColumn{Type: DurationType}, value: 90 * time.Minute, expected: "1h30m0s"},
+		{name: "testDurationString", column: MenuColumn{Type: DurationType}, value: "45m", expected: "45m0s"},
+		{
+			name:     "testTime",
+			column:   MenuColumn{Type: TimeType},
+			value:    time.Date(2024, 3, 9, 18, 30, 0, 0, time.UTC),
+			expected: "2024-03-09 18:30",
+		},
+		{name: "testTimeString", column: MenuColumn{Type: TimeType, Format: "Jan 2"}, value: "2024-03-09", expected: "Mar 9"},
+		{name: "testPercent", column: MenuColumn{Type: PercentType}, value: 0.25, expected: "25%"},
+		{name: "testPercentPrecision", column: MenuColumn{Type: PercentType, Precision: 1}, value: "12.34%", expected: "12.3%"},
+		{name: "testFloatStaysNumber", column: MenuColumn{Type: FloatType, Precision: 2}, value: 2.5, expected: 2.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, ok := tc.column.typedValue(tc.value)
+			if !ok {
+				t.Fatalf("couldn't convert %v to %v", tc.value, tc.column.Type)
+			}
+			displayed := tc.column.displayValue(value)
+			if displayed != tc.expected {
+				t.Errorf("got %v, expected %v", displayed, tc.expected)
+			}
+		})
+	}
+}
+
+func TestColumnValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		column      MenuColumn
+		expectedErr string
+	}{
+		{name: "testValid", column: MenuColumn{Type: FloatType, Precision: 2, Align: AlignCenter}},
+		{name: "testInvalidType", column: MenuColumn{Type: "money"}, expectedErr: `invalid column type "money"`},
+		{name: "testInvalidAlign", column: MenuColumn{Type: StringType, Align: 7}, expectedErr: "invalid alignment 7"},
+		{name: "testInvalidPrecision", column: MenuColumn{Type: FloatType, Precision: -2}, expectedErr: "invalid precision -2"},
+		{
+			name:        "testInvalidBoolFormat",
+			column:      MenuColumn{Type: BoolType, Format: "yes"},
+			expectedErr: `invalid bool format "yes", must be true and false text separated by /`,
+		},
+		{
+			name:        "testFormatForWrongType",
+			column:      MenuColumn{Type: IntType, Format: "%x"},
+			expectedErr: "format is only used for bool and time columns",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var menu Menu
+			err := menu.AddColumn(tc.column)
+			if tc.expectedErr == "" {
+				if err != nil || len(menu.Columns) != 1 {
+					t.Errorf("got error %v, expected column to be added", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+			}
+			if len(menu.Columns) != 0 {
+				t.Errorf("invalid column was added")
+			}
+		})
+	}
+}
+
+func TestShowMenuToleratesInvalidCells(t *testing.T) {
+	var out bytes.Buffer
+	var menu Menu
+	menu.Session = NewSession(strings.NewReader("back\n"), &out)
+	menu.Columns = []MenuColumn{
+		{ColWidth: 2, Type: IntType},
+		{ColWidth: 5, Type: StringType},
+		{ColWidth: 5, Type: StringType},
+		{ColWidth: 5, Type: IntType, Label: "Qty"},
+	}
+	menu.AddCommand(&Command{Name: "add", Cells: []interface{}{3}})
+	menu.AddCommand(&Command{Name: "edit", AdditionalColumns: []string{"three"}})
+	menu.AddCommand(&Command{Name: "back", Execute: BackFunc})
+
+	expectedErr := `command 2: invalid value "three" for column "Qty", must be of type int`
+	err := menu.Validate()
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("got error %v, expected %v", err, expectedErr)
+	}
+
+	// the menu is still shown, with the invalid cell as text
+	err = menu.MenuLoop()
+	if err != nil {
+		t.Fatalf("got error %v, expected the menu to be shown", err)
+	}
+	if !strings.Contains(out.String(), "three") {
+		t.Errorf("invalid cell not shown as text in:\n%s", out.String())
+	}
+
+	menu.Columns[1].Type = "text"
+	err = menu.Validate()
+	if err == nil || err.Error() != `column 2: invalid column type "text"` {
+		t.Errorf("got error %v, expected an invalid column type error", err)
+	}
+	out.Reset()
+	err = menu.ShowMenu()
+	if err != nil || !strings.Contains(out.String(), "edit") {
+		t.Errorf("got error %v, expected the menu to be shown as text in:\n%s", err, out.String())
+	}
+}
+
+func TestRenderAlignedColumns(t *testing.T) {
+	var out bytes.Buffer
+	var menu Menu
+	menu.Session = NewSession(nil, &out)
+	menu.Columns = []MenuColumn{
+		{ColWidth: 3, Type: IntType, Label: "#", Align: AlignLeft},
+		{ColWidth: -6, Type: StringType, Label: "Name", Align: AlignRight},
+		{ColWidth: 7, Type: StringType, Label: "Desc", Align: AlignCenter},
+		{ColWidth: 7, Type: FloatType, Label: "Qty", Precision: 2},
+		{ColWidth: 5, Type: BoolType, Label: "Veg", Align: AlignCenter},
+		{ColWidth: 5, Type: PercentType, Label: "Fat"},
+	}
+	menu.AddCommand(&Command{Name: "pasta", Description: "dinner", Cells: []interface{}{1.5, true, 0.125}})
+
+	err := menu.ShowMenu()
+	if err != nil {
+		t.Fatalf("got error %v", err.Error())
+	}
+
+	expected := "#     Name  Desc       Qty  Veg    Fat \n" +
+		strings.Repeat("-", 38) + "\n" +
+		"1    pasta dinner     1.50   ✓     12% \n"
+	if !strings.HasSuffix(out.String(), expected) {
+		t.Errorf("got %q, expected it to end with %q", out.String(), expected)
+	}
+}
@@ -0,0 +1,61 @@
+package climenus
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// returns the tab completions for the last word of line, the input typed so far at
+// this menu's prompt. The first word completes to the names of the menu's commands and
+// the built-in commands, later words to the values allowed for the command's ArgSpecs
+// (its Choices, and the values from its Complete function). Completions are sorted.
+func (menu *Menu) Completions(line string) []string {
+	words := strings.Fields(line)
+	// a line ending in a space is starting a new word
+	if len(words) == 0 || unicode.IsSpace(rune(line[len(line)-1])) {
+		words = append(words, "")
+	}
+	prefix := words[len(words)-1]
+
+	var candidates []string
+	if len(words) == 1 || (words[0] == helpInput && len(words) == 2) {
+		candidates = menu.commandNames()
+	} else if command, err := menu.Command(words[0]); err == nil {
+		argIdx := len(words) - 2
+		if argIdx < len(command.ArgSpecs) {
+			spec := command.ArgSpecs[argIdx]
+			candidates = slices.Clone(spec.Choices)
+			if spec.Complete != nil {
+				candidates = append(candidates, spec.Complete(prefix)...)
+			}
+		}
+	}
+
+	completions := make([]string, 0)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) && !slices.Contains(completions, candidate) {
+			completions = append(completions, candidate)
+		}
+	}
+	slices.Sort(completions)
+
+	return completions
+}
+
+// returns the names that can be entered to issue a command at this menu,
+// the names and aliases of its commands followed by the built-in commands
+func (menu *Menu) commandNames() []string {
+	names := make([]string, 0)
+	for _, command := range menu.Commands {
+		names = append(names, command.names()...)
+	}
+	for _, builtin := range menu.builtins() {
+		// commands entered with text after their name aren't completed
+		if !builtin.prefix {
+			names = append(names, builtin.name)
+		}
+	}
+
+	return names
+}
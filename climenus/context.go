@@ -0,0 +1,118 @@
+package climenus
+
+import (
+	"context"
+	"errors"
+)
+
+// error returned when no input is entered within a session's IdleTimeout
+var ErrIdleTimeout = errors.New("timed out waiting for input")
+
+// result of reading a line in the background
+type lineResult struct {
+	line string
+	err  error
+}
+
+// reads the next line like readLine, returning early with an error if ctx is done or
+// the session's IdleTimeout passes first, or a signal is trapped (see TrapSignals).
+// The read is then left running in the background, and the line it reads is returned
+// by the next read. A nil ctx reads without a context or idle timeout, which can only
+// be stopped by a trapped signal.
+func (s *Session) readLineContext(ctx context.Context, complete func(string) []string) (string, error) {
+	interrupts := s.trappedSignals()
+	readCtx := context.Background()
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		readCtx = ctx
+		if s.IdleTimeout > 0 {
+			var cancel context.CancelFunc
+			readCtx, cancel = context.WithTimeout(ctx, s.IdleTimeout)
+			defer cancel()
+		}
+	}
+
+	// reads that can't be stopped are read without a goroutine
+	if s.pending == nil && readCtx.Done() == nil && interrupts == nil {
+		return s.readLine(complete)
+	}
+	if s.pending == nil {
+		s.pending = make(chan lineResult, 1)
+		go func(result chan<- lineResult) {
+			line, err := s.readLine(complete)
+			result <- lineResult{line: line, err: err}
+		}(s.pending)
+	}
+
+	select {
+	case r := <-s.pending:
+		s.pending = nil
+		return r.line, r.err
+	case sig := <-interrupts:
+		// the terminal isn't left in raw mode while the line editor's read is waiting
+		s.restoreTerminal()
+		return "", signalError(sig)
+	case <-readCtx.Done():
+		s.restoreTerminal()
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", ErrIdleTimeout
+	}
+}
+
+// keeps the function restoring the terminal from raw mode while the line editor reads
+func (s *Session) setRestore(restore func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.restore = restore
+}
+
+// restores the terminal from raw mode if the line editor is reading
+func (s *Session) restoreTerminal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.restore != nil {
+		s.restore()
+		s.restore = nil
+	}
+}
+
+// takes validated input like UserInput, returning ctx.Err() if ctx is done before valid
+// input is entered, or ErrIdleTimeout if no input is entered within the IdleTimeout
+func (s *Session) UserInputContext(ctx context.Context, prompt string, validator func(string) (bool, error)) (string, error) {
+	return s.userInputContext(ctx, prompt, validator, nil, PlainTheme)
+}
+
+// takes validated user input like UserInputContext using this menu's session,
+// with the prompt and errors styled by the menu's theme
+func (menu *Menu) UserInputContext(ctx context.Context, prompt string, validator func(string) (bool, error)) (string, error) {
+	return menu.session().userInputContext(ctx, prompt, validator, nil, menu.theme())
+}
+
+// takes validated input like UserInputContext from the default session
+func UserInputContext(ctx context.Context, prompt string, validator func(string) (bool, error)) (string, error) {
+	return defaultSession.UserInputContext(ctx, prompt, validator)
+}
+
+// runs the menu loop like MenuLoop, returning ctx.Err() promptly once ctx is done, or
+// ErrIdleTimeout if no input is entered within the session's IdleTimeout. Prompts
+// shown by commands with UserInput are stopped as well, ending the loop.
+func (menu *Menu) MenuLoopContext(ctx context.Context) error {
+	session := menu.session()
+	previous := session.loopCtx
+	session.loopCtx = ctx
+	defer func() { session.loopCtx = previous }()
+	return menu.menuLoop()
+}
+
+// returns the context of the MenuLoop taking input from the session,
+// context.Background() outside of one
+func (s *Session) context() context.Context {
+	if s.loopCtx == nil {
+		return context.Background()
+	}
+	return s.loopCtx
+}
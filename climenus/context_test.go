@@ -0,0 +1,104 @@
+package climenus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// time after which a test cancels a context, while input is being waited for
+const cancelDelay = 20 * time.Millisecond
+
+func TestUserInputContextCancel(t *testing.T) {
+	in, input := io.Pipe()
+	defer input.Close()
+	var out bytes.Buffer
+	session := NewSession(in, &out)
+	acceptAll := func(string) (bool, error) { return true, nil }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(cancelDelay, cancel)
+	_, err := session.UserInputContext(ctx, "Name:", acceptAll)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, expected %v", err, context.Canceled)
+	}
+
+	// the line typed after the cancelled prompt is kept for the next one
+	go io.WriteString(input, "pasta\n")
+	name := session.UserInput("Name:", acceptAll)
+	if name != "pasta" {
+		t.Errorf("got %q, expected %q", name, "pasta")
+	}
+}
+
+func TestUserInputContextDeadline(t *testing.T) {
+	in, input := io.Pipe()
+	defer input.Close()
+	var out bytes.Buffer
+	menu := &Menu{Session: NewSession(in, &out)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cancelDelay)
+	defer cancel()
+	_, err := menu.UserInputContext(ctx, "Name:", func(string) (bool, error) { return true, nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, expected %v", err, context.DeadlineExceeded)
+	}
+
+	// a done context returns without prompting
+	out.Reset()
+	_, err = menu.UserInputContext(ctx, "Name:", func(string) (bool, error) { return true, nil })
+	if !errors.Is(err, context.DeadlineExceeded) || out.Len() != 0 {
+		t.Errorf("got error %v and output %q, expected to return without prompting", err, out.String())
+	}
+}
+
+func TestMenuLoopContextCancel(t *testing.T) {
+	in, input := io.Pipe()
+	defer input.Close()
+	var out bytes.Buffer
+	var numbers []string
+	menu := scriptTestMenu(&out, &numbers)
+	menu.Session = NewSession(in, &out)
+
+	// the context is cancelled while the command's prompt is waiting for a number
+	go io.WriteString(input, "add\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(cancelDelay, cancel)
+	err := menu.MenuLoopContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, expected %v", err, context.Canceled)
+	}
+	if len(numbers) != 0 {
+		t.Errorf("got numbers %v, expected the command to stop at its prompt", numbers)
+	}
+	if menu.Session.loopCtx != nil {
+		t.Errorf("session context wasn't reset")
+	}
+}
+
+func TestMenuLoopIdleTimeout(t *testing.T) {
+	in, input := io.Pipe()
+	defer input.Close()
+	var out bytes.Buffer
+	var numbers []string
+	menu := scriptTestMenu(&out, &numbers)
+	menu.Session = NewSession(in, &out)
+	menu.Session.IdleTimeout = 5 * cancelDelay
+
+	// input entered in time resets the timeout
+	go func() {
+		io.WriteString(input, "add\n")
+		time.Sleep(3 * cancelDelay)
+		io.WriteString(input, "1\n")
+	}()
+	err := menu.MenuLoop()
+	if !errors.Is(err, ErrIdleTimeout) {
+		t.Errorf("got error %v, expected %v", err, ErrIdleTimeout)
+	}
+	if len(numbers) != 1 {
+		t.Errorf("got numbers %v, expected the number entered in time", numbers)
+	}
+}
@@ -0,0 +1,86 @@
+package climenus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// error for input that has ended, at the end of a file or when Ctrl+D is pressed in
+// the line editor. Errors reading input wrap it as well, as input can't be read after them
+var ErrEndOfInput = errors.New("end of input")
+
+// what MenuLoop does when input ends
+type EOFPolicy int
+
+const (
+	EOFError EOFPolicy = iota // stop and return ErrEndOfInput
+	EOFBack                   // go back to the previous menu, or stop a command waiting for input
+	EOFExit                   // exit the program, returning an error matching ErrExitProgram and ErrEndOfInput
+)
+
+// returns ErrEndOfInput for an error ending input, wrapping it unless it is
+// io.EOF or nil (which bufio.Scanner gives at the end of input)
+func endOfInput(err error) error {
+	if err == nil || errors.Is(err, io.EOF) {
+		return ErrEndOfInput
+	}
+	return fmt.Errorf("%w: %w", ErrEndOfInput, err)
+}
+
+// returns the error MenuLoop stops with when input ends by err with the given
+// policy, or nil for EOFBack, which keeps the loop going
+func (p EOFPolicy) stopError(err error) error {
+	switch p {
+	case EOFBack:
+		return nil
+	case EOFExit:
+		return fmt.Errorf("%w: %w", ErrExitProgram, err)
+	}
+	return err
+}
+
+// error returned by runCommand when input stopped in a command stops the MenuLoop,
+// which returns err
+type loopStop struct {
+	err error
+}
+
+func (e *loopStop) Error() string {
+	return e.err.Error()
+}
+
+func (e *loopStop) Unwrap() error {
+	return e.err
+}
+
+// runs the command's Execute or Run function. If input is stopped while the command
+// takes it, the error stopping it is handled once the command returns, instead of
+// what the command returned. For the end of input, the session's OnEOF policy is
+// applied: nil is returned to go back to the menu for EOFBack, an exit error for
+// EOFExit, and the loop is stopped with ErrEndOfInput for EOFError. A trapped
+// interrupt cancels the command, and SIGTERM exits, see TrapSignals. Anything else
+// (e.g. a cancelled context) stops the loop, returned as a *loopStop.
+// Returns whether the command was called and returned, see Command.execute
+func (menu *Menu) runCommand(command *Command, args []string) (bool, error) {
+	session := menu.session()
+	// a MenuLoop run by a command keeps the input of the command running it apart
+	previous := session.stop
+	session.stop = nil
+	called, err := command.execute(args, menu)
+	stop := session.stop
+	session.stop = previous
+
+	switch {
+	case stop == nil:
+		return called, err
+	case errors.Is(stop, ErrInterrupted):
+		fmt.Fprintln(session.Out, cancelledMessage)
+		return false, nil
+	case errors.Is(stop, ErrTerminated):
+		return false, session.exitOnSignal(stop)
+	case errors.Is(stop, ErrEndOfInput) && session.OnEOF != EOFError:
+		return false, session.OnEOF.stopError(stop)
+	}
+	return false, &loopStop{err: stop}
+}
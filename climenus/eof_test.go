@@ -0,0 +1,115 @@
+package climenus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// reader that fails after returning its data
+type failingReader struct {
+	data string
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestMenuLoopEOFPolicy(t *testing.T) {
+	testCases := []struct {
+		name        string
+		policy      EOFPolicy
+		input       string
+		expectedErr error
+		numbers     []string
+	}{
+		{name: "testErrorAtMenu", policy: EOFError, input: "add\n1\n", expectedErr: ErrEndOfInput, numbers: []string{"1"}},
+		{name: "testErrorAtPrompt", policy: EOFError, input: "more\nadd\n", expectedErr: ErrEndOfInput},
+		{name: "testBackAtMenu", policy: EOFBack, input: "more\nadd\n2\n", numbers: []string{"2"}},
+		{name: "testBackAtPrompt", policy: EOFBack, input: "more\nadd\n"},
+		{name: "testExitAtMenu", policy: EOFExit, input: "more\n", expectedErr: ErrExitProgram},
+		{name: "testExitAtPrompt", policy: EOFExit, input: "add\n", expectedErr: ErrExitProgram},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			var numbers []string
+			menu := scriptTestMenu(&out, &numbers)
+			menu.Session = NewSession(strings.NewReader(tc.input), &out)
+			menu.Session.OnEOF = tc.policy
+
+			err := menu.MenuLoop()
+			if tc.expectedErr == nil && err != nil {
+				t.Errorf("got error %v, expected none", err)
+			} else if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+			}
+			if tc.policy == EOFExit && !errors.Is(err, ErrEndOfInput) {
+				t.Errorf("got error %v, expected it to match %v", err, ErrEndOfInput)
+			}
+			if strings.Join(numbers, ",") != strings.Join(tc.numbers, ",") {
+				t.Errorf("got numbers %v, expected %v", numbers, tc.numbers)
+			}
+		})
+	}
+}
+
+func TestUserInputEOF(t *testing.T) {
+	var out bytes.Buffer
+	session := NewSession(strings.NewReader("a\nb\n"), &out)
+	acceptAll := func(string) (bool, error) { return true, nil }
+
+	inputs := session.UserInputLoop("", "done", acceptAll)
+	if strings.Join(inputs, ",") != "a,b" {
+		t.Errorf("got %v, expected the inputs before the end of input", inputs)
+	}
+	if !errors.Is(session.Err(), ErrEndOfInput) {
+		t.Errorf("got error %v, expected %v", session.Err(), ErrEndOfInput)
+	}
+	if input := session.UserInput("", acceptAll); input != "" {
+		t.Errorf("got %q, expected no input", input)
+	}
+
+	_, err := session.UserInputContext(context.Background(), "", acceptAll)
+	if !errors.Is(err, ErrEndOfInput) {
+		t.Errorf("got error %v, expected %v", err, ErrEndOfInput)
+	}
+}
+
+func TestUserInputReadError(t *testing.T) {
+	var out bytes.Buffer
+	readErr := errors.New("connection reset")
+	menu := &Menu{Session: NewSession(&failingReader{data: "a\n", err: readErr}, &out)}
+	acceptAll := func(string) (bool, error) { return true, nil }
+
+	if input := menu.UserInput("", acceptAll); input != "a" || menu.Session.Err() != nil {
+		t.Errorf("got %q and error %v, expected %q", input, menu.Session.Err(), "a")
+	}
+	inputs := menu.UserInputLoop("", "done", acceptAll)
+	err := menu.Session.Err()
+	if len(inputs) != 0 || !errors.Is(err, ErrEndOfInput) || !errors.Is(err, readErr) {
+		t.Errorf("got %v and error %v, expected the read error", inputs, err)
+	}
+}
+
+func TestEndOfInput(t *testing.T) {
+	if err := endOfInput(nil); err != ErrEndOfInput {
+		t.Errorf("got %v, expected %v", err, ErrEndOfInput)
+	}
+	if err := endOfInput(io.EOF); err != ErrEndOfInput {
+		t.Errorf("got %v, expected %v", err, ErrEndOfInput)
+	}
+	if err := endOfInput(io.ErrClosedPipe); err.Error() != "end of input: io: read/write on closed pipe" {
+		t.Errorf("got %v, expected the read error to be wrapped", err)
+	}
+}
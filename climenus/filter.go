@@ -0,0 +1,58 @@
+package climenus
+
+import (
+	"fmt"
+	"strings"
+)
+
+// prefix for input that filters the commands shown, e.g. "/pasta".
+// The prefix alone clears the filter
+const filterInput = "/"
+
+// returns the text the menu's commands are filtered by, empty if they aren't filtered
+func (menu *Menu) Filter() string {
+	return menu.filter
+}
+
+// sets the text the menu's commands are filtered by, so only commands whose name,
+// description or additional columns contain it (ignoring case) are shown.
+// An empty filter shows all commands. Option numbers aren't changed by filtering,
+// and commands that aren't shown can still be issued.
+func (menu *Menu) SetFilter(filter string) {
+	menu.filter = strings.TrimSpace(filter)
+	menu.page = 0
+}
+
+// returns the commands that match the filter, all commands if there is no filter
+func (menu *Menu) filteredCommands() []*Command {
+	if menu.filter == "" {
+		return menu.Commands
+	}
+
+	filter := strings.ToLower(menu.filter)
+	commands := make([]*Command, 0)
+	for _, command := range menu.Commands {
+		if command.matchesFilter(filter) {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// returns true if the command's name, description or additional columns contain
+// the filter, which must be lower case
+func (command *Command) matchesFilter(filter string) bool {
+	for colIdx := nameColIdx; colIdx <= descriptionColIdx+command.additionalColumnCount(); colIdx++ {
+		contents := strings.ToLower(fmt.Sprint(command.columnContents(colIdx)))
+		if strings.Contains(contents, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// sets the filter from input starting with the filter prefix, for the filter
+// built-in command. args holds the input split into words
+func (menu *Menu) filterFromArgs(args []string) {
+	menu.SetFilter(strings.TrimPrefix(strings.Join(args, " "), filterInput))
+}
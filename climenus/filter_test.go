@@ -0,0 +1,101 @@
+package climenus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// returns a menu listing recipes, with a cuisine in an additional column
+func filterTestMenu() *Menu {
+	menu := &Menu{Columns: []MenuColumn{
+		{ColWidth: 3, Type: IntType, Label: "#"},
+		{ColWidth: 4, Type: StringType, Label: ""},
+		{ColWidth: -20, Type: StringType, Label: "Recipe"},
+		{ColWidth: -10, Type: StringType, Label: "Cuisine"},
+	}}
+	menu.AddCommand(&Command{Description: "Pasta Carbonara", AdditionalColumns: []string{"Italian"}})
+	menu.AddCommand(&Command{Description: "Pad Thai", AdditionalColumns: []string{"Thai"}})
+	menu.AddCommand(&Command{Description: "Pasta Salad", AdditionalColumns: []string{"Italian"}})
+	menu.AddCommand(&Command{Description: "Green Curry", AdditionalColumns: []string{"Thai"}})
+	menu.AddCommand(&Command{Name: "add", Description: "Add Recipe"})
+	return menu
+}
+
+func TestMenuFilter(t *testing.T) {
+	testCases := []struct {
+		filter   string
+		expected []int
+	}{
+		{filter: "", expected: []int{1, 2, 3, 4, 5}},
+		{filter: "pasta", expected: []int{1, 3}},
+		{filter: "THAI", expected: []int{2, 4}},
+		{filter: "ad", expected: []int{2, 3, 5}},
+		{filter: "  curry ", expected: []int{4}},
+		{filter: "nothing", expected: []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filter, func(t *testing.T) {
+			menu := filterTestMenu()
+			menu.SetFilter(tc.filter)
+
+			optionNumbers := make([]int, 0)
+			for _, command := range menu.pageCommands() {
+				optionNumbers = append(optionNumbers, command.OptionNumber)
+			}
+			if len(optionNumbers) != len(tc.expected) {
+				t.Fatalf("got %v, expected %v", optionNumbers, tc.expected)
+			}
+			for i := range optionNumbers {
+				if optionNumbers[i] != tc.expected[i] {
+					t.Errorf("got %v, expected %v", optionNumbers, tc.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestMenuFilterWithPages(t *testing.T) {
+	menu := filterTestMenu()
+	menu.PageSize = 1
+	menu.SetPage(3)
+
+	// filtering goes back to the first page of the filtered commands
+	menu.SetFilter("thai")
+	if menu.Page() != 1 || menu.PageCount() != 2 {
+		t.Errorf("got page %v of %v, expected page %v of %v", menu.Page(), menu.PageCount(), 1, 2)
+	}
+	menu.NextPage()
+	if footer := menu.pageFooter(); footer != "showing 2-2 of 2 (page 2 of 2)" {
+		t.Errorf("got %q, expected %q", footer, "showing 2-2 of 2 (page 2 of 2)")
+	}
+}
+
+func TestMenuLoopFilter(t *testing.T) {
+	var out bytes.Buffer
+	menu := filterTestMenu()
+	menu.Session = NewSession(strings.NewReader("/pasta\n/ nothing here\n/\nback\n"), &out)
+
+	err := menu.MenuLoop()
+	if err != nil {
+		t.Errorf("got error %v", err.Error())
+	}
+
+	// outputs of the menu after each input, the first is before any input
+	outputs := strings.Split(out.String(), "\n\n\n")
+	if len(outputs) != 5 {
+		t.Fatalf("got %v menu outputs, expected %v: %q", len(outputs), 5, out.String())
+	}
+	if !strings.Contains(outputs[2], `filter: "pasta" (enter / to clear)`) ||
+		!strings.Contains(outputs[2], "Pasta Salad") || strings.Contains(outputs[2], "Pad Thai") {
+		t.Errorf("output %q is not filtered by pasta", outputs[2])
+	}
+	if !strings.Contains(outputs[3], `filter: "nothing here"`) ||
+		!strings.Contains(outputs[3], "no commands match the filter") {
+		t.Errorf("output %q does not show that nothing matches", outputs[3])
+	}
+	if strings.Contains(outputs[4], "filter:") || !strings.Contains(outputs[4], "Pad Thai") {
+		t.Errorf("output %q is filtered after clearing the filter", outputs[4])
+	}
+}
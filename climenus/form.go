@@ -0,0 +1,551 @@
+package climenus
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// input that cancels a form at any of its prompts
+const formCancelInput = "cancel"
+
+// input that submits a form from its review screen
+const formSubmitInput = "submit"
+
+// input that ends the items of a list field
+const formDoneInput = "done"
+
+// input that removes the last item of a list field
+const formUndoInput = "undo"
+
+// separator between the items of a list field when it is shown
+const formListSeparator = "; "
+
+// error returned by Form.Run when the form is cancelled
+var ErrFormCancelled = errors.New("form cancelled")
+
+// type of the value a form field takes
+type FieldType int
+
+const (
+	TextField   FieldType = iota // a line of text, as a string
+	NumberField                  // a number, as a float64
+	ChoiceField                  // one of the field's Choices, entered by name or number, as a string
+	ListField                    // items entered one at a time until "done", as a []string or, with Parse, a []interface{}
+	YesNoField                   // y or n, as a bool
+)
+
+// struct describing a field of a Form
+type FormField struct {
+	Name  string    // key of the field's value in the form's values, and name to edit it by
+	Label string    // prompt for the field's value, the Name is used if empty
+	Help  string    // shown under the Label when prompting, e.g. the format of the value
+	Type  FieldType // type of the field's value
+	// value used when nothing is entered, of the type the field's values have
+	// (string, float64, bool or []string), or the text parsed for it with Parse.
+	// Values start empty if nil
+	Default interface{}
+	// whether a value must be entered when there is no Default
+	Required bool
+	// values a ChoiceField can take
+	Choices []string
+	// checks the text entered for the field before it is parsed, or each item of
+	// a ListField. Runs after the input is checked against the field's Type
+	Validator func(string) (bool, error)
+	// parses the text entered for a TextField, or each item of a ListField, into the
+	// value stored for it instead of the text, e.g. a struct. Runs after the Validator,
+	// and an error is shown and the input asked for again
+	Parse func(string) (interface{}, error)
+}
+
+// struct representing a form taking several values, one field at a time. After every
+// field is filled in, the values are shown on a review screen where any field can be
+// edited again before the form is submitted. Entering "cancel" cancels the form.
+type Form struct {
+	Title  string       // label for the form in breadcrumbs and its review screen
+	Fields []*FormField // fields of the form, prompted for in order
+}
+
+// values of a submitted form by field name, see FormField.Type for their types
+type FormValues map[string]interface{}
+
+// adds a field to the form, returns an error without adding it if it isn't valid
+func (f *Form) AddField(field *FormField) error {
+	if field.Name == "" {
+		return errors.New("form field must have a name")
+	}
+	if f.field(field.Name) != nil {
+		return fmt.Errorf("form already has a field named %q", field.Name)
+	}
+	if field.Type < TextField || field.Type > YesNoField {
+		return fmt.Errorf("field %q: invalid field type %d", field.Name, field.Type)
+	}
+	if field.Type == ChoiceField && len(field.Choices) == 0 {
+		return fmt.Errorf("field %q: choice fields must have choices", field.Name)
+	}
+	if field.Parse != nil && field.Type != TextField && field.Type != ListField {
+		return fmt.Errorf("field %q: only text and list fields can have Parse", field.Name)
+	}
+	if field.Default != nil {
+		value, ok := field.defaultValue()
+		if !ok || (field.Type == ChoiceField && field.choiceIndex(value.(string)) < 0) {
+			return fmt.Errorf("field %q: invalid default %v", field.Name, field.Default)
+		}
+		value, err := field.parseDefault(value)
+		if err != nil {
+			return fmt.Errorf("field %q: invalid default %v: %w", field.Name, field.Default, err)
+		}
+		field.Default = value
+	}
+
+	f.Fields = append(f.Fields, field)
+	return nil
+}
+
+// returns the field with the given name, nil if there isn't one
+func (f *Form) field(name string) *FormField {
+	for _, field := range f.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// prompts for each field of the form with the menu's session, then shows the
+// values for review until they're submitted. Returns the values entered, or
+// ErrFormCancelled if the form is cancelled. If input ends or is stopped first, the
+// error is returned, e.g. ErrEndOfInput, see Menu.UserInputErr
+func (f *Form) Run(menu *Menu) (FormValues, error) {
+	values := make(FormValues, len(f.Fields))
+	fmt.Fprintf(menu.Out(), "\n%s\nEnter '%s' at any prompt to cancel.\n", f.Title, formCancelInput)
+
+	for _, field := range f.Fields {
+		value, err := field.prompt(menu, field.Default)
+		if err != nil {
+			return nil, err
+		}
+		values[field.Name] = value
+	}
+
+	for {
+		field, err := f.review(menu, values)
+		if err != nil {
+			return nil, err
+		}
+		if field == nil {
+			return values, nil
+		}
+
+		value, err := field.prompt(menu, values[field.Name])
+		if err != nil {
+			return nil, err
+		}
+		values[field.Name] = value
+	}
+}
+
+// takes validated input with the menu's session, returning ErrFormCancelled if the
+// form is cancelled, or the error if input ends or is stopped, see Menu.UserInputErr
+func formInput(menu *Menu, prompt string, validator func(string) (bool, error)) (string, error) {
+	input, err := menu.UserInputErr(prompt, validator)
+	if err != nil {
+		return "", err
+	}
+	if input == formCancelInput {
+		return "", ErrFormCancelled
+	}
+	return input, nil
+}
+
+// shows the values on the review screen and asks for a field to edit, or to submit.
+// Returns the field to edit, nil if the form is submitted, or an error if it is
+// cancelled or input ends
+func (f *Form) review(menu *Menu, values FormValues) (*FormField, error) {
+	review := &Menu{
+		Title: f.Title,
+		Instructions: fmt.Sprintf("Enter the number or name of a field to edit it, '%s' to finish or '%s' to cancel",
+			formSubmitInput, formCancelInput),
+		Columns: []MenuColumn{
+			{ColWidth: 2, Type: IntType, Label: "#"},
+			{MinWidth: 10, Type: StringType, Label: "Field", Align: AlignLeft, Fraction: 0.3},
+			{MinWidth: 20, Type: StringType, Label: "Value", Align: AlignLeft},
+		},
+		parent: menu,
+	}
+	for _, field := range f.Fields {
+		review.AddCommand(&Command{Name: field.label(), Description: field.format(values[field.Name])})
+	}
+	// the review screen is built here, so its rows are always valid
+	review.ShowMenu()
+
+	var selected *FormField
+	validator := func(input string) (bool, error) {
+		if input == formSubmitInput || input == formCancelInput {
+			return true, nil
+		}
+		if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(f.Fields) {
+			selected = f.Fields[n-1]
+			return true, nil
+		}
+		for _, field := range f.Fields {
+			if strings.EqualFold(input, field.Name) || strings.EqualFold(input, field.label()) {
+				selected = field
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("%q isn't a field, enter a field number, '%s' or '%s'",
+			input, formSubmitInput, formCancelInput)
+	}
+
+	input, err := formInput(menu, "", validator)
+	if err != nil || input == formSubmitInput {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// prompts for the field's value, with current shown as the default. Returns
+// the value entered, or an error if the form is cancelled or input ends
+func (field *FormField) prompt(menu *Menu, current interface{}) (interface{}, error) {
+	if field.Type == ListField {
+		return field.promptList(menu, current)
+	}
+
+	var value interface{}
+	validator := func(input string) (bool, error) {
+		if input == formCancelInput {
+			return true, nil
+		}
+		if input == "" {
+			if current == nil && field.Required {
+				return false, errors.New("a value is required")
+			}
+			value = current
+			return true, nil
+		}
+
+		parsed, err := field.parse(input)
+		if err != nil {
+			return false, err
+		}
+		if field.Validator != nil {
+			isValid, err := field.Validator(input)
+			if !isValid {
+				return false, err
+			}
+		}
+		if field.Parse != nil {
+			parsed, err = field.Parse(input)
+			if err != nil {
+				return false, err
+			}
+		}
+		value = parsed
+		return true, nil
+	}
+
+	if _, err := formInput(menu, field.promptText(current), validator); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		value = field.zeroValue()
+	}
+	return value, nil
+}
+
+// prompts for the items of a list field one at a time until "done", starting from
+// the current items. Returns the items, or an error if the form is cancelled or input ends
+func (field *FormField) promptList(menu *Menu, current interface{}) (interface{}, error) {
+	items := listItems(current)
+	if len(items) > 0 {
+		fmt.Fprintf(menu.Out(), "current %s: %s\n", field.label(), field.format(items))
+	}
+
+	var item interface{}
+	validator := func(input string) (bool, error) {
+		switch input {
+		case formCancelInput, formUndoInput:
+			return true, nil
+		case formDoneInput:
+			if len(items) == 0 && field.Required {
+				return false, errors.New("at least one item is required")
+			}
+			return true, nil
+		case "":
+			return false, fmt.Errorf("enter an item, or '%s' when done", formDoneInput)
+		}
+		if field.Validator != nil {
+			isValid, err := field.Validator(input)
+			if !isValid {
+				return false, err
+			}
+		}
+		if field.Parse != nil {
+			var err error
+			item, err = field.Parse(input)
+			return err == nil, err
+		}
+		item = input
+		return true, nil
+	}
+
+	for {
+		input, err := formInput(menu, field.promptText(nil), validator)
+		if err != nil {
+			return nil, err
+		}
+		switch input {
+		case formDoneInput:
+			return field.listValue(items), nil
+		case formUndoInput:
+			if len(items) > 0 {
+				fmt.Fprintf(menu.Out(), "removed %q\n", field.format(items[len(items)-1]))
+				items = items[:len(items)-1]
+			}
+		default:
+			items = append(items, item)
+		}
+	}
+}
+
+// returns a copy of the items of a list value, nil if it isn't one
+func listItems(value interface{}) []interface{} {
+	var items []interface{}
+	switch v := value.(type) {
+	case []string:
+		for _, item := range v {
+			items = append(items, item)
+		}
+	case []interface{}:
+		items = append(items, v...)
+	}
+	return items
+}
+
+// returns the items as the value of the list field, a []string unless it has Parse
+func (field *FormField) listValue(items []interface{}) interface{} {
+	if field.Parse != nil {
+		return append([]interface{}{}, items...)
+	}
+	texts := make([]string, len(items))
+	for i, item := range items {
+		texts[i] = item.(string)
+	}
+	return texts
+}
+
+// returns the prompt for the field, with hints for its type and the value kept
+// if nothing is entered
+func (field *FormField) promptText(current interface{}) string {
+	var sb strings.Builder
+	sb.WriteString("\n" + field.label())
+	switch field.Type {
+	case NumberField:
+		sb.WriteString(" (number)")
+	case YesNoField:
+		sb.WriteString(" (y/n)")
+	case ListField:
+		fmt.Fprintf(&sb, " (one at a time, '%s' when done, '%s' to remove the last)", formDoneInput, formUndoInput)
+	}
+	if current != nil && field.Type != ListField {
+		fmt.Fprintf(&sb, " [%s]", field.format(current))
+	}
+	if field.Help != "" {
+		sb.WriteString("\n" + field.Help)
+	}
+	if field.Type == ChoiceField {
+		for i, choice := range field.Choices {
+			fmt.Fprintf(&sb, "\n%3d %s", i+1, choice)
+		}
+	}
+	return sb.String()
+}
+
+// returns the label of the field, its Name if it has no Label
+func (field *FormField) label() string {
+	if field.Label == "" {
+		return field.Name
+	}
+	return field.Label
+}
+
+// parses input for the field into a value of the field's type
+func (field *FormField) parse(input string) (interface{}, error) {
+	switch field.Type {
+	case NumberField:
+		return ParseFloat(input)
+	case ChoiceField:
+		return ParseChoice(field.Choices...)(input)
+	case YesNoField:
+		return ParseYesNo(input)
+	}
+	return input, nil
+}
+
+// parses a default given as text for a field with Parse, each item for a ListField
+func (field *FormField) parseDefault(value interface{}) (interface{}, error) {
+	if field.Parse == nil {
+		return value, nil
+	}
+	if field.Type != ListField {
+		return field.Parse(value.(string))
+	}
+	items := listItems(value)
+	for i, item := range items {
+		parsed, err := field.Parse(item.(string))
+		if err != nil {
+			return nil, err
+		}
+		items[i] = parsed
+	}
+	return field.listValue(items), nil
+}
+
+// returns the index of the choice matching s ignoring case, -1 if there is none
+func (field *FormField) choiceIndex(s string) int {
+	for i, choice := range field.Choices {
+		if strings.EqualFold(s, choice) {
+			return i
+		}
+	}
+	return -1
+}
+
+// returns the field's Default as a value of the field's type, false if it
+// can't be one. Int defaults of number fields are converted to float64
+func (field *FormField) defaultValue() (interface{}, bool) {
+	switch d := field.Default.(type) {
+	case string:
+		return d, field.Type == TextField || field.Type == ChoiceField
+	case float64:
+		return d, field.Type == NumberField
+	case int:
+		return float64(d), field.Type == NumberField
+	case bool:
+		return d, field.Type == YesNoField
+	case []string:
+		return d, field.Type == ListField
+	}
+	return nil, false
+}
+
+// returns the value of the field's type used when nothing is entered without a default
+func (field *FormField) zeroValue() interface{} {
+	switch field.Type {
+	case NumberField:
+		return 0.0
+	case YesNoField:
+		return false
+	case ListField:
+		return field.listValue(nil)
+	}
+	return ""
+}
+
+// returns a value of the field as it is shown on the review screen
+func (field *FormField) format(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	case []string:
+		return strings.Join(v, formListSeparator)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = field.format(item)
+		}
+		return strings.Join(items, formListSeparator)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// copies the values to the fields of the struct dst points to. Struct fields are
+// matched to form fields by a `form:"name"` tag, or else by name ignoring case.
+// Number values can be stored in int and float fields, as long as ints get whole numbers
+func (v FormValues) Decode(dst interface{}) error {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Struct {
+		return errors.New("form values can only be decoded into a pointer to a struct")
+	}
+	s := ptr.Elem()
+
+	for i := range s.NumField() {
+		structField := s.Type().Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		value, ok := v.lookup(structField)
+		if !ok {
+			continue
+		}
+		err := setField(s.Field(i), value)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", structField.Name, err)
+		}
+	}
+	return nil
+}
+
+// returns the value for a struct field, by its form tag or else its name ignoring case
+func (v FormValues) lookup(structField reflect.StructField) (interface{}, bool) {
+	if name, ok := structField.Tag.Lookup("form"); ok {
+		value, ok := v[name]
+		return value, ok
+	}
+	for name, value := range v {
+		if strings.EqualFold(name, structField.Name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// sets a struct field to a form value, converting numbers to the field's type
+// and the items of parsed lists to the field's element type. A nil value sets the zero value
+func setField(field reflect.Value, value interface{}) error {
+	if items, ok := value.([]interface{}); ok && field.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setField(slice.Index(i), item); err != nil {
+				return fmt.Errorf("item %d: %w", i+1, err)
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	// nil values, e.g. a Parse result, leave the field empty
+	if !rv.IsValid() {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if f, ok := value.(float64); ok {
+		switch field.Kind() {
+		case reflect.Float32, reflect.Float64:
+			field.SetFloat(f)
+			return nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if f != math.Trunc(f) || field.OverflowInt(int64(f)) {
+				return fmt.Errorf("%v isn't a whole number that fits in %s", f, field.Type())
+			}
+			field.SetInt(int64(f))
+			return nil
+		}
+	}
+	if !rv.Type().AssignableTo(field.Type()) {
+		return fmt.Errorf("can't store %T in %s", value, field.Type())
+	}
+	field.Set(rv)
+	return nil
+}
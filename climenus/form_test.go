@@ -0,0 +1,297 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// returns a form with a field of each type
+func testForm(t *testing.T) *Form {
+	form := &Form{Title: "New recipe"}
+	fields := []*FormField{
+		{Name: "name", Label: "Recipe name", Type: TextField, Required: true, Validator: func(s string) (bool, error) {
+			if len(s) > 10 {
+				return false, errors.New("name is too long")
+			}
+			return true, nil
+		}},
+		{Name: "servings", Type: NumberField, Default: 2, Help: "how many people it serves"},
+		{Name: "meal", Type: ChoiceField, Choices: []string{"breakfast", "lunch", "dinner"}, Default: "dinner"},
+		{Name: "ingredients", Type: ListField, Required: true},
+		{Name: "vegetarian", Type: YesNoField},
+	}
+	for _, field := range fields {
+		if err := form.AddField(field); err != nil {
+			t.Fatalf("got error %v adding field %s", err, field.Name)
+		}
+	}
+	return form
+}
+
+// runs the test form with the given input from a menu
+func runTestForm(t *testing.T, input string) (FormValues, error, string) {
+	var out bytes.Buffer
+	menu := &Menu{Title: "Recipes"}
+	menu.Session = NewSession(strings.NewReader(input), &out)
+	menu.Session.Width = 60
+	values, err := testForm(t).Run(menu)
+	return values, err, out.String()
+}
+
+func TestFormRun(t *testing.T) {
+	input := strings.Join([]string{
+		"pasta with sauce", // too long, prompted again
+		"pasta",
+		"two", // not a number, prompted again
+		"4",
+		"LUNCH",
+		"",     // no items entered yet, prompted again
+		"done", // at least one item is required, prompted again
+		"flour",
+		"eggs",
+		"salt",
+		"undo",
+		"done",
+		"maybe",
+		"y",
+		// review: edit the meal by number and the ingredients by name, then submit
+		"3",
+		"1",
+		"ingredients",
+		"salt",
+		"done",
+		"submit",
+	}, "\n") + "\n"
+
+	values, err, out := runTestForm(t, input)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	expected := FormValues{
+		"name":        "pasta",
+		"servings":    4.0,
+		"meal":        "breakfast",
+		"ingredients": []string{"flour", "eggs", "salt"},
+		"vegetarian":  true,
+	}
+	for name, value := range expected {
+		if (&FormField{}).format(values[name]) != (&FormField{}).format(value) {
+			t.Errorf("got %v for %s, expected %v", values[name], name, value)
+		}
+	}
+
+	for _, message := range []string{
+		"name is too long",
+		"must be a number",
+		"enter an item, or 'done' when done",
+		"at least one item is required",
+		`removed "salt"`,
+		"must enter either 'Y' or 'N'",
+		"current ingredients: flour; eggs",
+		"Recipes > New recipe",
+		" 2 servings         4     ",
+	} {
+		if !strings.Contains(out, message) {
+			t.Errorf("expected output to contain %q", message)
+		}
+	}
+}
+
+func TestFormDefaults(t *testing.T) {
+	values, err, out := runTestForm(t, "soup\n\n\nwater\ndone\n\nsubmit\n")
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if values["servings"] != 2.0 || values["meal"] != "dinner" || values["vegetarian"] != false {
+		t.Errorf("got %v, expected the defaults", values)
+	}
+	if !strings.Contains(out, "servings (number) [2]\nhow many people it serves") || !strings.Contains(out, "meal [dinner]\n  1 breakfast") {
+		t.Errorf("got %q, expected the defaults to be shown", out)
+	}
+}
+
+func TestFormCancel(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{name: "testCancelField", input: "soup\ncancel\n"},
+		{name: "testCancelList", input: "soup\n\n\nwater\ncancel\n"},
+		{name: "testCancelReview", input: "soup\n\n\nwater\ndone\n\ncancel\n"},
+		{name: "testCancelEdit", input: "soup\n\n\nwater\ndone\n\n1\ncancel\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err, _ := runTestForm(t, tc.input)
+			if !errors.Is(err, ErrFormCancelled) || values != nil {
+				t.Errorf("got %v, %v, expected the form to be cancelled", values, err)
+			}
+		})
+	}
+}
+
+func TestFormEndOfInput(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{name: "testEndAtField", input: "soup\n"},
+		{name: "testEndInList", input: "soup\n\n\nwater\n"},
+		{name: "testEndAtReview", input: "soup\n\n\nwater\ndone\n\n"},
+		{name: "testEndInEdit", input: "soup\n\n\nwater\ndone\n\n4\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err, _ := runTestForm(t, tc.input)
+			if !errors.Is(err, ErrEndOfInput) || values != nil {
+				t.Errorf("got %v, %v, expected the form to stop with %v", values, err, ErrEndOfInput)
+			}
+		})
+	}
+}
+
+// item of the parsed list field in TestFormParse
+type testQuantity struct {
+	Name   string
+	Amount int
+}
+
+func (q testQuantity) String() string {
+	return fmt.Sprintf("%d %s", q.Amount, q.Name)
+}
+
+// parses "name, amount" into a testQuantity
+func parseTestQuantity(s string) (interface{}, error) {
+	name, amount, ok := strings.Cut(s, ",")
+	n, err := strconv.Atoi(strings.TrimSpace(amount))
+	if !ok || err != nil {
+		return nil, errors.New("enter a name and an amount")
+	}
+	return testQuantity{Name: strings.TrimSpace(name), Amount: n}, nil
+}
+
+func TestFormParse(t *testing.T) {
+	form := &Form{Title: "Shopping"}
+	fields := []*FormField{
+		{Name: "first", Type: TextField, Parse: parseTestQuantity, Default: "milk, 1"},
+		{Name: "items", Type: ListField, Parse: parseTestQuantity, Validator: func(s string) (bool, error) {
+			if strings.HasPrefix(s, "-") {
+				return false, errors.New("no negative names")
+			}
+			return true, nil
+		}},
+	}
+	for _, field := range fields {
+		if err := form.AddField(field); err != nil {
+			t.Fatalf("got error %v adding field %s", err, field.Name)
+		}
+	}
+
+	var out bytes.Buffer
+	menu := newTestMenu("\n-eggs, 2\neggs\neggs, 6\nflour, 2\ndone\nsubmit\n", &out)
+	values, err := form.Run(menu)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	var shopping struct {
+		First testQuantity
+		Items []testQuantity
+	}
+	if err := values.Decode(&shopping); err != nil {
+		t.Fatalf("got error %v decoding", err)
+	}
+	if shopping.First != (testQuantity{"milk", 1}) || fmt.Sprint(shopping.Items) != "[6 eggs 2 flour]" {
+		t.Errorf("got %+v", shopping)
+	}
+	for _, message := range []string{"first [1 milk]", "no negative names", "enter a name and an amount", "6 eggs; 2 flour"} {
+		if !strings.Contains(out.String(), message) {
+			t.Errorf("expected output to contain %q, got:\n%s", message, out.String())
+		}
+	}
+}
+
+func TestFormAddField(t *testing.T) {
+	testCases := []struct {
+		name        string
+		field       *FormField
+		expectedErr string
+	}{
+		{name: "testNoName", field: &FormField{}, expectedErr: "form field must have a name"},
+		{name: "testDuplicate", field: &FormField{Name: "meal"}, expectedErr: `form already has a field named "meal"`},
+		{name: "testInvalidType", field: &FormField{Name: "a", Type: 9}, expectedErr: `field "a": invalid field type 9`},
+		{name: "testNoChoices", field: &FormField{Name: "a", Type: ChoiceField}, expectedErr: `field "a": choice fields must have choices`},
+		{
+			name:        "testDefaultNotAChoice",
+			field:       &FormField{Name: "a", Type: ChoiceField, Choices: []string{"b"}, Default: "c"},
+			expectedErr: `field "a": invalid default c`,
+		},
+		{name: "testDefaultWrongType", field: &FormField{Name: "a", Type: YesNoField, Default: "y"}, expectedErr: `field "a": invalid default y`},
+		{
+			name:        "testParseNotText",
+			field:       &FormField{Name: "a", Type: NumberField, Parse: parseTestQuantity},
+			expectedErr: `field "a": only text and list fields can have Parse`,
+		},
+		{
+			name:        "testDefaultNotParsed",
+			field:       &FormField{Name: "a", Type: ListField, Parse: parseTestQuantity, Default: []string{"eggs"}},
+			expectedErr: `field "a": invalid default [eggs]: enter a name and an amount`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			form := testForm(t)
+			err := form.AddField(tc.field)
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+			}
+			if len(form.Fields) != 5 {
+				t.Errorf("invalid field was added")
+			}
+		})
+	}
+}
+
+func TestFormValuesDecode(t *testing.T) {
+	var recipe struct {
+		Name        string
+		Servings    int
+		Meal        string   `form:"meal"`
+		Items       []string `form:"ingredients"`
+		Vegetarian  bool
+		notExported string
+	}
+	values := FormValues{"name": "pasta", "servings": 4.0, "meal": "lunch", "ingredients": []string{"flour"}, "vegetarian": true}
+	if err := values.Decode(&recipe); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if recipe.Name != "pasta" || recipe.Servings != 4 || recipe.Meal != "lunch" ||
+		strings.Join(recipe.Items, ",") != "flour" || !recipe.Vegetarian {
+		t.Errorf("got %+v", recipe)
+	}
+
+	values["servings"] = 2.5
+	if err := values.Decode(&recipe); err == nil || err.Error() != "field Servings: 2.5 isn't a whole number that fits in int" {
+		t.Errorf("got error %v, expected a whole number error", err)
+	}
+	values["servings"] = "four"
+	if err := values.Decode(&recipe); err == nil || err.Error() != "field Servings: can't store string in int" {
+		t.Errorf("got error %v, expected a type error", err)
+	}
+	values["servings"] = nil
+	values["ingredients"] = []interface{}{"salt", nil}
+	if err := values.Decode(&recipe); err != nil || recipe.Servings != 0 || fmt.Sprintf("%q", recipe.Items) != `["salt" ""]` {
+		t.Errorf("got %+v and error %v, expected nil values to be stored as zero values", recipe, err)
+	}
+	if err := values.Decode(recipe); err == nil {
+		t.Errorf("expected an error decoding into a struct value")
+	}
+}
@@ -0,0 +1,125 @@
+package climenus
+
+import (
+	"fmt"
+	"strings"
+)
+
+// returns the help for this menu, listing its commands with their usage and
+// description, followed by the built-in commands available in every menu
+func (menu *Menu) Help() string {
+	// pad usages to the same width so descriptions line up
+	width := 0
+	for _, command := range menu.Commands {
+		width = max(width, len(command.Usage()))
+	}
+	for _, builtin := range menu.builtins() {
+		width = max(width, len(builtin.usage))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Commands:\n")
+	for _, command := range menu.Commands {
+		fmt.Fprintf(&sb, "  %3d  %-*s  %s\n", command.OptionNumber, width, command.Usage(), command.Description)
+	}
+
+	sb.WriteString("\nAvailable in every menu:\n")
+	for _, builtin := range menu.builtins() {
+		fmt.Fprintf(&sb, "       %-*s  %s\n", width, builtin.usage, builtin.description)
+	}
+
+	fmt.Fprintf(&sb, "\nEnter \"%s <command>\" for more about a command.\n", helpInput)
+
+	return sb.String()
+}
+
+// returns the detailed help for this command: its usage, aliases, description,
+// help text, arguments and examples
+func (command *Command) HelpText() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "usage: %s\n", command.Usage())
+	if len(command.Aliases) > 0 {
+		fmt.Fprintf(&sb, "aliases: %s\n", strings.Join(command.Aliases, ", "))
+	}
+
+	if command.Description != "" {
+		fmt.Fprintf(&sb, "\n%s\n", command.Description)
+	}
+	if command.Help != "" {
+		fmt.Fprintf(&sb, "\n%s\n", strings.TrimSpace(command.Help))
+	}
+
+	if len(command.ArgSpecs) > 0 {
+		width := 0
+		for _, spec := range command.ArgSpecs {
+			width = max(width, len(spec.Name))
+		}
+
+		sb.WriteString("\nArguments:\n")
+		for _, spec := range command.ArgSpecs {
+			fmt.Fprintf(&sb, "  %-*s  %s", width, spec.Name, spec.summary())
+			if spec.Description != "" {
+				fmt.Fprintf(&sb, " - %s", spec.Description)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(command.Examples) > 0 {
+		sb.WriteString("\nExamples:\n")
+		for _, example := range command.Examples {
+			fmt.Fprintf(&sb, "  %s\n", example)
+		}
+	}
+
+	return sb.String()
+}
+
+// returns a short summary of the argument's type and whether it's required,
+// e.g. "(float, required)" or "(string, optional, default g, one of g|kg)"
+func (spec *ArgSpec) summary() string {
+	argType := spec.Type
+	if argType == "" {
+		argType = StringType
+	}
+
+	parts := []string{argType}
+	if spec.Required {
+		parts = append(parts, "required")
+	} else {
+		parts = append(parts, "optional")
+	}
+	if spec.Default != "" {
+		parts = append(parts, "default "+spec.Default)
+	} else if spec.HasDefault {
+		parts = append(parts, `default ""`)
+	}
+	if len(spec.Choices) > 0 {
+		parts = append(parts, "one of "+strings.Join(spec.Choices, "|"))
+	}
+
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// prints the help for this menu, or for the command named in args[0]
+// (a command of the menu by name or option number, or a built-in command)
+func (menu *Menu) showHelp(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(menu.Out(), menu.Help())
+		return nil
+	}
+
+	command, err := menu.Command(args[0])
+	if err == nil {
+		fmt.Fprint(menu.Out(), command.HelpText())
+		return nil
+	}
+
+	builtin := menu.findBuiltin(args[0])
+	if builtin == nil {
+		return fmt.Errorf("no help for %s: %s", args[0], err.Error())
+	}
+
+	fmt.Fprintf(menu.Out(), "usage: %s\n\n%s\n", builtin.usage, builtin.description)
+	return nil
+}
@@ -0,0 +1,85 @@
+package climenus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCommandHelpText(t *testing.T) {
+	command := Command{
+		Name:        "scale",
+		Description: "Scale the recipe",
+		Help:        "Multiplies every ingredient quantity by the factor.",
+		ArgSpecs:    scaleArgSpecs,
+		Examples:    []string{"scale 2", "scale 0.5 kg"},
+	}
+
+	expected := "usage: scale <factor> [unit=g|kg] [servings]\n" +
+		"\nScale the recipe\n" +
+		"\nMultiplies every ingredient quantity by the factor.\n" +
+		"\nArguments:\n" +
+		"  factor    (float, required)\n" +
+		"  unit      (string, optional, default g, one of g|kg)\n" +
+		"  servings  (int, optional)\n" +
+		"\nExamples:\n" +
+		"  scale 2\n" +
+		"  scale 0.5 kg\n"
+
+	if command.HelpText() != expected {
+		t.Errorf("got %q, expected %q", command.HelpText(), expected)
+	}
+}
+
+func TestMenuLoopHelp(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:  "testMenuHelp",
+			input: "help\nback\n",
+			expected: []string{
+				"Commands:\n    1  add             Add a recipe\n    2  2               Pasta\n",
+				"       goto <path>     go to a menu by its path",
+				"       help [command]  show help for this menu, or for a command\n",
+			},
+		},
+		{
+			name:     "testCommandHelp",
+			input:    "help add\nhelp 2\nback\n",
+			expected: []string{"usage: add\n\nAdd a recipe\n\nPrompts for a name.\n", "usage: 2\n\nPasta\n"},
+		},
+		{
+			name:     "testBuiltinHelp",
+			input:    "help home\nback\n",
+			expected: []string{"usage: home\n\ngo back to the top level menu\n"},
+		},
+		{
+			name:     "testUnknownCommandHelp",
+			input:    "help nothing\nback\n",
+			expected: []string{"no help for nothing: invalid command"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			var menu Menu
+			menu.Session = NewSession(strings.NewReader(tc.input), &out)
+			menu.AddCommand(&Command{Name: "add", Description: "Add a recipe", Help: "Prompts for a name."})
+			menu.AddCommand(&Command{Description: "Pasta"})
+
+			err := menu.MenuLoop()
+			if err != nil {
+				t.Errorf("got error %v", err.Error())
+			}
+			for _, expected := range tc.expected {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("output %q does not contain %q", out.String(), expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package climenus
+
+import "math"
+
+// returns true if the column's width depends on the terminal width
+func (c *MenuColumn) isFlexible() bool {
+	return c.Fraction > 0 || c.MinWidth > 0
+}
+
+// returns the columns of this menu with the widths they are printed with.
+// Fixed columns keep their ColWidth, flexible columns are given a width
+// from the width of the session's terminal. Widths are signed for the
+// column's Align, negative for left aligned columns. The lines of the
+// menu's border are left room for
+func (menu *Menu) layout() []MenuColumn {
+	for _, column := range menu.Columns {
+		if column.isFlexible() {
+			termWidth := menu.session().TerminalWidth() - menu.border().extraWidth(len(menu.Columns))
+			return alignColumns(layoutColumns(menu.Columns, termWidth))
+		}
+	}
+	return alignColumns(menu.Columns)
+}
+
+// returns a copy of the columns with the ColWidth of flexible columns set for a
+// terminal termWidth columns wide. The width left after the fixed columns (and the
+// space after each column) is shared out: columns with a Fraction get that fraction
+// of it, then columns with only a MinWidth share the rest equally. Flexible columns
+// are never narrower than their MinWidth (or 1), even if the table then doesn't fit.
+func layoutColumns(columns []MenuColumn, termWidth int) []MenuColumn {
+	laidOut := make([]MenuColumn, len(columns))
+	copy(laidOut, columns)
+
+	// width left for flexible columns, after the fixed columns and the space after each column
+	remaining := termWidth - len(columns)
+	totalFraction := 0.0
+	minOnlyColumns := 0
+	for _, column := range columns {
+		switch {
+		case !column.isFlexible():
+			remaining -= int(math.Abs(float64(column.ColWidth)))
+		case column.Fraction > 0:
+			totalFraction += column.Fraction
+		default:
+			minOnlyColumns++
+		}
+	}
+	remaining = max(remaining, 0)
+
+	// fractions adding up to more than 1 are scaled down to share the width between them
+	scale := 1.0
+	if totalFraction > 1 {
+		scale = 1 / totalFraction
+	}
+
+	unused := remaining
+	for i := range laidOut {
+		if laidOut[i].Fraction > 0 {
+			width := int(laidOut[i].Fraction * scale * float64(remaining))
+			laidOut[i].ColWidth = withJustification(laidOut[i].ColWidth, max(width, laidOut[i].MinWidth, 1))
+			unused -= width
+		}
+	}
+	for i := range laidOut {
+		if laidOut[i].Fraction <= 0 && laidOut[i].MinWidth > 0 {
+			width := max(unused, 0) / minOnlyColumns
+			laidOut[i].ColWidth = withJustification(laidOut[i].ColWidth, max(width, laidOut[i].MinWidth))
+		}
+	}
+
+	return laidOut
+}
+
+// returns width with the sign of colWidth, negative for left justified columns
+func withJustification(colWidth int, width int) int {
+	if colWidth < 0 {
+		return -width
+	}
+	return width
+}
@@ -0,0 +1,118 @@
+package climenus
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLayoutColumns(t *testing.T) {
+	testCases := []struct {
+		name      string
+		columns   []MenuColumn
+		termWidth int
+		expected  []int
+	}{
+		{
+			name:      "testFixedOnly",
+			columns:   []MenuColumn{{ColWidth: 3}, {ColWidth: -10}},
+			termWidth: 80,
+			expected:  []int{3, -10},
+		},
+		{
+			name:      "testMinWidthFills",
+			columns:   []MenuColumn{{ColWidth: 3}, {ColWidth: -1, MinWidth: 10}},
+			termWidth: 80,
+			expected:  []int{3, -75},
+		},
+		{
+			name:      "testMinWidthOnNarrowTerminal",
+			columns:   []MenuColumn{{ColWidth: 3}, {MinWidth: 10}},
+			termWidth: 12,
+			expected:  []int{3, 10},
+		},
+		{
+			name:      "testFractions",
+			columns:   []MenuColumn{{ColWidth: 4}, {Fraction: 0.25}, {ColWidth: -1, Fraction: 0.75}},
+			termWidth: 47,
+			expected:  []int{4, 10, -30},
+		},
+		{
+			name:      "testFractionsOverOne",
+			columns:   []MenuColumn{{Fraction: 1}, {Fraction: 1}},
+			termWidth: 42,
+			expected:  []int{20, 20},
+		},
+		{
+			name:      "testFractionAndMinWidthShareRest",
+			columns:   []MenuColumn{{ColWidth: 9}, {Fraction: 0.5}, {MinWidth: 5}, {MinWidth: 5}},
+			termWidth: 90,
+			expected:  []int{9, 38, 19, 19},
+		},
+		{
+			name:      "testFractionBelowMinWidth",
+			columns:   []MenuColumn{{ColWidth: 10}, {Fraction: 0.1, MinWidth: 8}},
+			termWidth: 40,
+			expected:  []int{10, 8},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			laidOut := layoutColumns(tc.columns, tc.termWidth)
+			for i, column := range laidOut {
+				if column.ColWidth != tc.expected[i] {
+					t.Errorf("column %v: got %v, expected %v", i, column.ColWidth, tc.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSessionTerminalWidth(t *testing.T) {
+	session := NewSession(nil, io.Discard)
+
+	t.Setenv(columnsEnv, "")
+	if width := session.TerminalWidth(); width != defaultTerminalWidth {
+		t.Errorf("got %v, expected %v", width, defaultTerminalWidth)
+	}
+
+	t.Setenv(columnsEnv, "120")
+	if width := session.TerminalWidth(); width != 120 {
+		t.Errorf("got %v, expected %v", width, 120)
+	}
+
+	t.Setenv(columnsEnv, "wide")
+	if width := session.TerminalWidth(); width != defaultTerminalWidth {
+		t.Errorf("got %v, expected %v", width, defaultTerminalWidth)
+	}
+}
+
+func TestShowMenuFollowsTerminalWidth(t *testing.T) {
+	var out bytes.Buffer
+	var menu Menu
+	menu.Session = NewSession(nil, &out)
+	menu.Columns = []MenuColumn{
+		{ColWidth: 2, Type: IntType, Label: "#"},
+		{ColWidth: -1, MinWidth: 4, Type: StringType, Label: "Name"},
+	}
+	menu.AddCommand(&Command{Name: "boil the pasta"})
+
+	// the menu is laid out again when the terminal is resized between renders
+	t.Setenv(columnsEnv, "20")
+	menu.ShowMenu()
+	if !strings.Contains(out.String(), " 1 boil the pasta   \n") {
+		t.Errorf("output %q is not laid out for 20 columns", out.String())
+	}
+
+	out.Reset()
+	t.Setenv(columnsEnv, "12")
+	menu.ShowMenu()
+	if !strings.Contains(out.String(), " 1 boil the \n   pasta    \n") {
+		t.Errorf("output %q is not laid out for 12 columns", out.String())
+	}
+	if menu.Columns[1].ColWidth != -1 {
+		t.Errorf("layout changed the menu's column width to %v", menu.Columns[1].ColWidth)
+	}
+}
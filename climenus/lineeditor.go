@@ -0,0 +1,332 @@
+package climenus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// max number of history entries kept by a LineEditor without MaxHistory set
+const defaultMaxHistory = 500
+
+// keys handled by the line editor
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyCtrlH     = 8
+	keyTab       = 9
+	keyLineFeed  = 10
+	keyCtrlK     = 11
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyBackspace = 127
+)
+
+// struct for an interactive line editor, used by a Session to read input from a terminal.
+// Supports cursor movement (arrow keys, Home/End, Ctrl+A/E), deleting (Backspace, Delete,
+// Ctrl+U/K/W), history (up/down arrows) and tab completion.
+type LineEditor struct {
+	HistoryFile string // file that history is loaded from and saved to, history isn't saved if empty
+	MaxHistory  int    // max number of history entries kept, defaults to 500 if 0
+
+	history       []string // previous input lines, oldest first
+	historyLoaded bool     // whether history has been loaded from HistoryFile
+}
+
+// creates a new line editor that keeps its history in historyFile,
+// history is only kept in memory if historyFile is empty
+func NewLineEditor(historyFile string) *LineEditor {
+	return &LineEditor{HistoryFile: historyFile}
+}
+
+// returns the input history, oldest first
+func (e *LineEditor) History() []string {
+	e.loadHistory()
+	return slices.Clone(e.history)
+}
+
+// adds a line to the history and saves the history to HistoryFile if set.
+// Blank lines and repeats of the last line aren't added.
+func (e *LineEditor) AddHistory(line string) error {
+	e.loadHistory()
+	if strings.TrimSpace(line) == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return nil
+	}
+
+	e.history = append(e.history, line)
+	maxHistory := e.MaxHistory
+	if maxHistory <= 0 {
+		maxHistory = defaultMaxHistory
+	}
+	if len(e.history) > maxHistory {
+		e.history = e.history[len(e.history)-maxHistory:]
+	}
+
+	if e.HistoryFile == "" {
+		return nil
+	}
+	return os.WriteFile(e.HistoryFile, []byte(strings.Join(e.history, "\n")+"\n"), 0644)
+}
+
+// loads the history from HistoryFile the first time history is needed,
+// a missing file is treated as empty history
+func (e *LineEditor) loadHistory() {
+	if e.historyLoaded {
+		return
+	}
+	e.historyLoaded = true
+
+	if e.HistoryFile == "" {
+		return
+	}
+	data, err := os.ReadFile(e.HistoryFile)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			e.history = append(e.history, line)
+		}
+	}
+}
+
+// state of the line currently being edited
+type editState struct {
+	out        io.Writer
+	line       []rune // contents of the line
+	pos        int    // cursor position within line
+	historyIdx int    // index of the history entry shown, len(history) for the new line
+	newLine    []rune // the new line, kept while browsing history
+}
+
+// redraws the line, and moves the cursor to its position. The cursor is moved back
+// by the display width of the text after it, since wide runes take two columns
+func (s *editState) refresh() {
+	fmt.Fprintf(s.out, "\r%s\x1b[K", string(s.line))
+	if back := displayWidth(string(s.line[s.pos:])); back > 0 {
+		fmt.Fprintf(s.out, "\x1b[%dD", back)
+	}
+}
+
+// inserts runes at the cursor
+func (s *editState) insert(runes ...rune) {
+	s.line = slices.Insert(s.line, s.pos, runes...)
+	s.pos += len(runes)
+}
+
+// deletes the runes between from and to, leaving the cursor at from
+func (s *editState) delete(from int, to int) {
+	if from < 0 || to > len(s.line) || from >= to {
+		return
+	}
+	s.line = slices.Delete(s.line, from, to)
+	s.pos = from
+}
+
+// replaces the line with the given contents, with the cursor at the end
+func (s *editState) setLine(line []rune) {
+	s.line = slices.Clone(line)
+	s.pos = len(s.line)
+}
+
+// reads keys from in and edits a line until enter is pressed, echoing the line to out.
+// complete returns completions for the word before the cursor given the line up to the
+// cursor, and may be nil. Returns the line and any read error, io.EOF if Ctrl+D is
+// pressed on an empty line, and ErrInterrupted if Ctrl+C is pressed.
+func (e *LineEditor) edit(in *bufio.Reader, out io.Writer, complete func(string) []string) (string, error) {
+	e.loadHistory()
+	s := &editState{out: out, historyIdx: len(e.history)}
+
+	for {
+		r, _, err := in.ReadRune()
+		if err != nil {
+			fmt.Fprint(out, "\r\n")
+			return string(s.line), err
+		}
+
+		switch r {
+		case keyEnter, keyLineFeed:
+			fmt.Fprint(out, "\r\n")
+			line := string(s.line)
+			// history is a convenience, failing to save it shouldn't fail the input
+			e.AddHistory(strings.TrimSpace(line))
+			return line, nil
+		case keyCtrlD:
+			if len(s.line) == 0 {
+				fmt.Fprint(out, "\r\n")
+				return "", io.EOF
+			}
+			s.delete(s.pos, s.pos+1)
+		case keyCtrlC:
+			fmt.Fprint(out, "^C\r\n")
+			return string(s.line), ErrInterrupted
+		case keyBackspace, keyCtrlH:
+			s.delete(s.pos-1, s.pos)
+		case keyCtrlA:
+			s.pos = 0
+		case keyCtrlE:
+			s.pos = len(s.line)
+		case keyCtrlB:
+			s.pos = max(s.pos-1, 0)
+		case keyCtrlF:
+			s.pos = min(s.pos+1, len(s.line))
+		case keyCtrlU:
+			s.delete(0, s.pos)
+		case keyCtrlK:
+			s.delete(s.pos, len(s.line))
+		case keyCtrlW:
+			s.delete(prevWordStart(s.line, s.pos), s.pos)
+		case keyCtrlP:
+			e.historyPrev(s)
+		case keyCtrlN:
+			e.historyNext(s)
+		case keyTab:
+			e.completeWord(s, complete)
+		case keyEscape:
+			e.handleEscape(in, s)
+		default:
+			if r >= ' ' {
+				s.insert(r)
+			}
+		}
+
+		s.refresh()
+	}
+}
+
+// handles the escape sequences sent for arrow keys, Home, End and Delete
+func (e *LineEditor) handleEscape(in *bufio.Reader, s *editState) {
+	r, _, err := in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return
+	}
+
+	// sequences are either a single letter, or numbers ended by '~'
+	var sb strings.Builder
+	for {
+		r, _, err = in.ReadRune()
+		if err != nil {
+			return
+		}
+		if r < '0' || r > '9' {
+			break
+		}
+		sb.WriteRune(r)
+	}
+
+	switch {
+	case r == 'A':
+		e.historyPrev(s)
+	case r == 'B':
+		e.historyNext(s)
+	case r == 'C':
+		s.pos = min(s.pos+1, len(s.line))
+	case r == 'D':
+		s.pos = max(s.pos-1, 0)
+	case r == 'H' || (r == '~' && (sb.String() == "1" || sb.String() == "7")):
+		s.pos = 0
+	case r == 'F' || (r == '~' && (sb.String() == "4" || sb.String() == "8")):
+		s.pos = len(s.line)
+	case r == '~' && sb.String() == "3":
+		s.delete(s.pos, s.pos+1)
+	}
+}
+
+// shows the previous history entry
+func (e *LineEditor) historyPrev(s *editState) {
+	if s.historyIdx == 0 {
+		return
+	}
+	if s.historyIdx == len(e.history) {
+		s.newLine = slices.Clone(s.line)
+	}
+	s.historyIdx--
+	s.setLine([]rune(e.history[s.historyIdx]))
+}
+
+// shows the next history entry, or the new line after the last entry
+func (e *LineEditor) historyNext(s *editState) {
+	if s.historyIdx >= len(e.history) {
+		return
+	}
+	s.historyIdx++
+	if s.historyIdx == len(e.history) {
+		s.setLine(s.newLine)
+	} else {
+		s.setLine([]rune(e.history[s.historyIdx]))
+	}
+}
+
+// completes the word before the cursor. A single completion replaces the word,
+// several completions are extended to their common prefix, or listed if there is none.
+func (e *LineEditor) completeWord(s *editState, complete func(string) []string) {
+	if complete == nil {
+		return
+	}
+
+	start := wordStart(s.line, s.pos)
+	word := string(s.line[start:s.pos])
+	completions := make([]string, 0)
+	for _, completion := range complete(string(s.line[:s.pos])) {
+		if strings.HasPrefix(completion, word) && !slices.Contains(completions, completion) {
+			completions = append(completions, completion)
+		}
+	}
+
+	switch len(completions) {
+	case 0:
+		fmt.Fprint(s.out, "\a")
+	case 1:
+		s.delete(start, s.pos)
+		s.insert([]rune(completions[0] + " ")...)
+	default:
+		prefix := commonPrefix(completions)
+		if len(prefix) > len(word) {
+			s.delete(start, s.pos)
+			s.insert([]rune(prefix)...)
+			return
+		}
+		fmt.Fprintf(s.out, "\r\n%s\r\n", strings.Join(completions, "  "))
+	}
+}
+
+// returns the index of the start of the word ending at pos,
+// which is pos itself if the rune before pos is a space
+func wordStart(line []rune, pos int) int {
+	start := pos
+	for start > 0 && line[start-1] != ' ' {
+		start--
+	}
+	return start
+}
+
+// returns the index of the start of the word before pos, skipping any spaces before pos
+func prevWordStart(line []rune, pos int) int {
+	start := pos
+	for start > 0 && line[start-1] == ' ' {
+		start--
+	}
+	return wordStart(line, start)
+}
+
+// returns the longest prefix shared by all of the strings
+func commonPrefix(values []string) string {
+	prefix := []rune(values[0])
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, string(prefix)) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return string(prefix)
+}
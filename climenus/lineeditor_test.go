@@ -0,0 +1,144 @@
+package climenus
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLineEditorEdit(t *testing.T) {
+	completions := func(line string) []string {
+		return []string{"add", "adjust", "delete"}
+	}
+
+	testCases := []struct {
+		name        string
+		keys        string
+		history     []string
+		expected    string
+		expectedErr error
+	}{
+		{name: "testTyping", keys: "hello\r", expected: "hello"},
+		{name: "testArrowKeys", keys: "ab\x1b[Dc\x1b[C\x1b[Cd\r", expected: "acbd"},
+		{name: "testHomeEnd", keys: "mid\x01<\x05>\x1b[H[\x1b[F]\r", expected: "[<mid>]"},
+		{name: "testBackspaceDelete", keys: "abcd\x7f\x1b[D\x1b[D\x1b[3~\r", expected: "ac"},
+		{name: "testKillLine", keys: "one two\x1b[D\x1b[D\x0b\x05\x15three\r", expected: "three"},
+		{name: "testDeleteWord", keys: "one two  \x17\r", expected: "one "},
+		{name: "testUnicode", keys: "crème\x1b[D\x7f\r", expected: "crèe"},
+		{name: "testHistoryUp", keys: "\x1b[A\x1b[A\r", history: []string{"first", "second"}, expected: "first"},
+		{name: "testHistoryBackToNewLine", keys: "new\x1b[A\x1b[B\r", history: []string{"first"}, expected: "new"},
+		{name: "testCompleteUnique", keys: "d\t2\r", expected: "delete 2"},
+		{name: "testCompleteCommonPrefix", keys: "a\tj\t\r", expected: "adjust "},
+		{name: "testCompleteNoMatch", keys: "x\t\r", expected: "x"},
+		{name: "testCtrlDEmpty", keys: "\x04", expected: "", expectedErr: io.EOF},
+		{name: "testEndOfInput", keys: "partial", expected: "partial", expectedErr: io.EOF},
+		{name: "testCtrlC", keys: "partial\x03more\r", expected: "partial", expectedErr: ErrInterrupted},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			editor := NewLineEditor("")
+			for _, line := range tc.history {
+				editor.AddHistory(line)
+			}
+
+			var out bytes.Buffer
+			line, err := editor.edit(bufio.NewReader(strings.NewReader(tc.keys)), &out, completions)
+			if err != tc.expectedErr {
+				t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+			}
+			if line != tc.expected {
+				t.Errorf("got %q, expected %q", line, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLineEditorRefreshWideRunes(t *testing.T) {
+	var out bytes.Buffer
+	state := editState{out: &out, line: []rune("食べるa"), pos: 1}
+	state.refresh()
+
+	// the cursor goes back over "べる" (two columns each) and "a"
+	expected := "\r食べるa\x1b[K\x1b[5D"
+	if out.String() != expected {
+		t.Errorf("got %q, expected %q", out.String(), expected)
+	}
+}
+
+func TestLineEditorListsCompletions(t *testing.T) {
+	editor := NewLineEditor("")
+	completions := func(line string) []string { return []string{"add", "back"} }
+
+	var out bytes.Buffer
+	editor.edit(bufio.NewReader(strings.NewReader("\t\r")), &out, completions)
+	if !strings.Contains(out.String(), "\r\nadd  back\r\n") {
+		t.Errorf("output %q does not list completions", out.String())
+	}
+}
+
+func TestLineEditorHistoryFile(t *testing.T) {
+	historyFile := filepath.Join(t.TempDir(), "history")
+
+	editor := NewLineEditor(historyFile)
+	editor.MaxHistory = 2
+	for _, line := range []string{"one", "two", "two", "", "three"} {
+		err := editor.AddHistory(line)
+		if err != nil {
+			t.Fatalf("got error %v", err.Error())
+		}
+	}
+
+	reloaded := NewLineEditor(historyFile)
+	history := reloaded.History()
+	if strings.Join(history, ",") != "two,three" {
+		t.Errorf("got %v, expected %v", history, []string{"two", "three"})
+	}
+}
+
+func TestSessionEditorFallback(t *testing.T) {
+	// input that isn't a terminal is read as plain lines, even with an editor set
+	session := NewSession(strings.NewReader("plain\x1b[D line\n"), io.Discard)
+	session.Editor = NewLineEditor("")
+
+	input := session.UserInput("", func(string) (bool, error) { return true, nil })
+	if input != "plain\x1b[D line" {
+		t.Errorf("got %q, expected %q", input, "plain\x1b[D line")
+	}
+}
+
+func TestMenuCompletions(t *testing.T) {
+	var menu Menu
+	menu.AddCommand(&Command{Name: "scale", ArgSpecs: []ArgSpec{
+		{Name: "factor", Type: FloatType, Complete: func(prefix string) []string { return []string{"0.5", "2"} }},
+		{Name: "unit", Choices: []string{"kg", "g"}},
+	}})
+	menu.AddCommand(&Command{Name: "save"})
+	menu.AddCommand(&Command{Description: "unnamed"})
+
+	testCases := []struct {
+		line     string
+		expected []string
+	}{
+		{line: "", expected: []string{"back", "goto", "help", "home", "save", "scale"}},
+		{line: "s", expected: []string{"save", "scale"}},
+		{line: "scale ", expected: []string{"0.5", "2"}},
+		{line: "1 2 ", expected: []string{"g", "kg"}},
+		{line: "scale 2 k", expected: []string{"kg"}},
+		{line: "scale 2 kg ", expected: []string{}},
+		{line: "help sc", expected: []string{"scale"}},
+		{line: "nothing ", expected: []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			completions := menu.Completions(tc.line)
+			if strings.Join(completions, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("got %v, expected %v", completions, tc.expected)
+			}
+		})
+	}
+}
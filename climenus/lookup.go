@@ -0,0 +1,93 @@
+package climenus
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// error returned when a command prefix matches more than one command
+type AmbiguousCommandError struct {
+	Input      string   // the prefix that was entered
+	Candidates []string // names of the commands the prefix matches, sorted
+}
+
+// returns the error message listing the candidates, e.g.
+// ambiguous command "s", could be: save, scale
+func (e *AmbiguousCommandError) Error() string {
+	return fmt.Sprintf("ambiguous command %q, could be: %s", e.Input, strings.Join(e.Candidates, ", "))
+}
+
+// looks up a command by its name or one of its aliases. An exact match is tried first,
+// then a match ignoring case if CaseInsensitive is set, then a unique prefix if
+// MatchPrefixes is set. Built-in commands entered exactly aren't matched as prefixes,
+// so "back" still goes back in a menu with a "backup" command.
+func (menu *Menu) lookupCommand(name string) (*Command, error) {
+	// commands without a name are only selected by their option number
+	if name == "" {
+		return nil, errors.New("invalid command")
+	}
+	command, isValidCommand := menu.CommandsMap[name]
+	if isValidCommand {
+		return command, nil
+	}
+
+	if menu.CaseInsensitive {
+		for _, command := range menu.Commands {
+			for _, commandName := range command.names() {
+				if strings.EqualFold(commandName, name) {
+					return command, nil
+				}
+			}
+		}
+	}
+
+	if !menu.MatchPrefixes || menu.isBuiltinInput(name) {
+		return nil, errors.New("invalid command")
+	}
+
+	matches := make([]*Command, 0)
+	for _, command := range menu.Commands {
+		if command.hasPrefix(name, menu.CaseInsensitive) {
+			matches = append(matches, command)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, errors.New("invalid command")
+	case 1:
+		return matches[0], nil
+	}
+
+	candidates := make([]string, 0, len(matches))
+	for _, match := range matches {
+		candidates = append(candidates, match.names()[0])
+	}
+	slices.Sort(candidates)
+	return nil, &AmbiguousCommandError{Input: name, Candidates: candidates}
+}
+
+// returns the names the command can be entered by, its name followed by its aliases
+func (command *Command) names() []string {
+	names := make([]string, 0, len(command.Aliases)+1)
+	if command.Name != "" {
+		names = append(names, command.Name)
+	}
+	return append(names, command.Aliases...)
+}
+
+// returns true if the command's name or one of its aliases starts with prefix
+func (command *Command) hasPrefix(prefix string, ignoreCase bool) bool {
+	for _, name := range command.names() {
+		if ignoreCase {
+			name = strings.ToLower(name)
+			prefix = strings.ToLower(prefix)
+		}
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// returns a menu with commands add (alias "new"), adjust, delete (alias "rm") and backup
+func lookupTestMenu(caseInsensitive bool, matchPrefixes bool) *Menu {
+	menu := &Menu{CaseInsensitive: caseInsensitive, MatchPrefixes: matchPrefixes}
+	menu.AddCommand(&Command{Name: "add", Aliases: []string{"new"}})
+	menu.AddCommand(&Command{Name: "adjust"})
+	menu.AddCommand(&Command{Name: "delete", Aliases: []string{"rm"}})
+	menu.AddCommand(&Command{Name: "backup"})
+	return menu
+}
+
+func TestMenuCommandLookup(t *testing.T) {
+	testCases := []struct {
+		name            string
+		input           string
+		caseInsensitive bool
+		matchPrefixes   bool
+		expected        string
+		expectedErr     string
+	}{
+		{name: "testExactName", input: "add", expected: "add"},
+		{name: "testAlias", input: "rm", expected: "delete"},
+		{name: "testOptionNumber", input: "2", expected: "adjust"},
+		{name: "testCaseSensitiveByDefault", input: "ADD", expectedErr: "invalid command"},
+		{name: "testCaseInsensitive", input: "ADD", caseInsensitive: true, expected: "add"},
+		{name: "testCaseInsensitiveAlias", input: "Rm", caseInsensitive: true, expected: "delete"},
+		{name: "testNoPrefixByDefault", input: "del", expectedErr: "invalid command"},
+		{name: "testUniquePrefix", input: "del", matchPrefixes: true, expected: "delete"},
+		{name: "testUniqueAliasPrefix", input: "ne", matchPrefixes: true, expected: "add"},
+		{name: "testExactBeatsPrefix", input: "add", matchPrefixes: true, expected: "add"},
+		{name: "testPrefixIgnoringCase", input: "DEL", caseInsensitive: true, matchPrefixes: true, expected: "delete"},
+		{name: "testPrefixNeedsCase", input: "DEL", matchPrefixes: true, expectedErr: "invalid command"},
+		{
+			name:          "testAmbiguousPrefix",
+			input:         "ad",
+			matchPrefixes: true,
+			expectedErr:   `ambiguous command "ad", could be: add, adjust`,
+		},
+		{name: "testBuiltinNotPrefix", input: "back", matchPrefixes: true, expectedErr: "invalid command"},
+		{name: "testNoMatch", input: "x", matchPrefixes: true, expectedErr: "invalid command"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			menu := lookupTestMenu(tc.caseInsensitive, tc.matchPrefixes)
+
+			command, err := menu.Command(tc.input)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("got error %v", err.Error())
+			}
+			if command.Name != tc.expected {
+				t.Errorf("got %v, expected %v", command.Name, tc.expected)
+			}
+		})
+	}
+}
+
+func TestAmbiguousCommandError(t *testing.T) {
+	menu := lookupTestMenu(false, true)
+
+	_, err := menu.commandValidator("ad 2")
+	var ambiguousErr *AmbiguousCommandError
+	if !errors.As(err, &ambiguousErr) {
+		t.Fatalf("got error %v, expected an AmbiguousCommandError", err)
+	}
+	if strings.Join(ambiguousErr.Candidates, ",") != "add,adjust" {
+		t.Errorf("got %v, expected %v", ambiguousErr.Candidates, []string{"add", "adjust"})
+	}
+}
+
+func TestMenuLoopAliasesAndPrefixes(t *testing.T) {
+	var out bytes.Buffer
+	menu := &Menu{CaseInsensitive: true, MatchPrefixes: true}
+	menu.Session = NewSession(strings.NewReader("s\nSA\nnew\nbac\nback\n"), &out)
+
+	issued := make([]string, 0)
+	record := func(args []string, m *Menu) error {
+		issued = append(issued, args[0])
+		return nil
+	}
+	menu.AddCommand(&Command{Name: "save", Execute: record})
+	menu.AddCommand(&Command{Name: "scale", Execute: record})
+	menu.AddCommand(&Command{Name: "add", Aliases: []string{"new"}, Execute: record})
+	menu.AddCommand(&Command{Name: "backup", Execute: record})
+
+	err := menu.MenuLoop()
+	if err != nil {
+		t.Errorf("got error %v", err.Error())
+	}
+
+	if strings.Join(issued, ",") != "SA,new,bac" {
+		t.Errorf("got %v, expected %v", issued, []string{"SA", "new", "bac"})
+	}
+	if !strings.Contains(out.String(), `ambiguous command "s", could be: save, scale`) {
+		t.Errorf("output %q does not contain ambiguity error", out.String())
+	}
+}
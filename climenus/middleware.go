@@ -0,0 +1,128 @@
+package climenus
+
+import (
+	"errors"
+	"fmt"
+)
+
+// function handling an issued command, given the command, its args and the menu it was
+// issued from. For commands using Execute, args holds the input words in Raw without Values
+type Handler func(command *Command, args *Args, menu *Menu) error
+
+// function wrapping the Handler of a command, e.g. to log or time it, check it may be
+// run, or recover from panics. Returning without calling next stops the command from
+// running or opening its SubMenu, and the error returned is handled by MenuLoop as if
+// the command returned it
+type Middleware func(next Handler) Handler
+
+// middleware run around the commands of every menu, see Use
+var globalMiddleware []Middleware
+
+// adds middleware run around the commands of every menu, outside the middleware of the
+// menus themselves. Middleware runs in the order it is added, the first is outermost
+func Use(middleware ...Middleware) {
+	globalMiddleware = append(globalMiddleware, middleware...)
+}
+
+// returns Middleware calling before ahead of each command,
+// the command isn't run if before returns an error, which is returned instead
+func Before(before func(command *Command, args *Args, menu *Menu) error) Middleware {
+	return func(next Handler) Handler {
+		return func(command *Command, args *Args, menu *Menu) error {
+			if err := before(command, args, menu); err != nil {
+				return err
+			}
+			return next(command, args, menu)
+		}
+	}
+}
+
+// returns Middleware calling after once each command has run, with the error it
+// returned. The error returned by after is returned for the command instead
+func After(after func(command *Command, args *Args, menu *Menu, err error) error) Middleware {
+	return func(next Handler) Handler {
+		return func(command *Command, args *Args, menu *Menu) error {
+			return after(command, args, menu, next(command, args, menu))
+		}
+	}
+}
+
+// returns Middleware calling onError when a command fails, with the error it returned.
+// The error returned by onError is returned for the command instead, nil to ignore it.
+// Errors for going back, navigating and exiting aren't failures, see isControlError
+func OnError(onError func(command *Command, args *Args, menu *Menu, err error) error) Middleware {
+	return After(func(command *Command, args *Args, menu *Menu, err error) error {
+		if err == nil || isControlError(err) {
+			return err
+		}
+		return onError(command, args, menu, err)
+	})
+}
+
+// error for a command that panicked, returned by the Recover middleware
+type PanicError struct {
+	Command *Command    // command that panicked
+	Value   interface{} // value the command panicked with
+}
+
+func (e *PanicError) Error() string {
+	name := e.Command.Name
+	if name == "" {
+		name = fmt.Sprint(e.Command.OptionNumber)
+	}
+	return fmt.Sprintf("command %s failed: %v", name, e.Value)
+}
+
+// returns Middleware recovering from commands that panic, returning a *PanicError
+// so MenuLoop prints it and carries on
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return func(command *Command, args *Args, menu *Menu) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				err = &PanicError{Command: command, Value: r}
+			}()
+			return next(command, args, menu)
+		}
+	}
+}
+
+// returns true for errors commands return to go back, navigate or exit the program
+func isControlError(err error) bool {
+	var navErr *NavigationError
+	return errors.Is(err, ErrBack) || errors.Is(err, ErrExitProgram) || errors.As(err, &navErr)
+}
+
+// returns the handler running commands issued from this menu with inner, wrapped in
+// the global middleware, then the middleware of the menus this one was opened from,
+// outermost first, and then this menu's own middleware
+func (menu *Menu) handler(inner Handler) Handler {
+	handler := inner
+	for m := menu; m != nil; m = m.parent {
+		handler = wrapHandler(handler, m.Middleware)
+	}
+	return wrapHandler(handler, globalMiddleware)
+}
+
+// wraps the handler in the middleware, with the first middleware outermost
+func wrapHandler(handler Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// Handler calling the command's Run function with args if set, otherwise
+// Execute with the input words. Commands that only open a SubMenu do nothing
+func callCommand(command *Command, args *Args, menu *Menu) error {
+	if command.Run != nil {
+		return command.Run(args, menu)
+	}
+	if command.Execute != nil {
+		return command.Execute(args.Raw, menu)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// returns Middleware adding name to calls before running the command
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return Before(func(command *Command, args *Args, menu *Menu) error {
+		*calls = append(*calls, name)
+		return nil
+	})
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	previous := globalMiddleware
+	defer func() { globalMiddleware = previous }()
+
+	var calls []string
+	Use(recordingMiddleware("global", &calls))
+	subMenu := &Menu{Columns: []MenuColumn{{ColWidth: -5, Type: StringType, Label: "Name"}}}
+	subMenu.AddCommand(&Command{Name: "run", Execute: func(args []string, menu *Menu) error {
+		calls = append(calls, "run")
+		return nil
+	}})
+	subMenu.Middleware = []Middleware{recordingMiddleware("sub", &calls)}
+	menu := &Menu{Columns: subMenu.Columns}
+	menu.AddCommand(&Command{Name: "more", SubMenu: subMenu})
+	menu.Middleware = []Middleware{recordingMiddleware("main1", &calls), recordingMiddleware("main2", &calls)}
+	menu.Session = NewSession(strings.NewReader("more\nrun\n"), &bytes.Buffer{})
+
+	menu.MenuLoop()
+	expected := "global,main1,main2,global,main1,main2,sub,run"
+	if strings.Join(calls, ",") != expected {
+		t.Errorf("got calls %v, expected %s", calls, expected)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	var out bytes.Buffer
+	var numbers []string
+	menu := scriptTestMenu(&out, &numbers)
+	menu.Session = NewSession(strings.NewReader("add skip\nmore\nadd\n3\n"), &out)
+	entered := false
+	menu.Commands[2].SubMenu.Refresh = func(*Menu) error {
+		entered = true
+		return nil
+	}
+	menu.Middleware = []Middleware{
+		Before(func(command *Command, args *Args, menu *Menu) error {
+			if command.Name == "more" {
+				return errors.New("not allowed")
+			}
+			return nil
+		}),
+		func(next Handler) Handler {
+			return func(command *Command, args *Args, menu *Menu) error {
+				if len(args.Raw) > 1 && args.Raw[1] == "skip" {
+					return nil
+				}
+				return next(command, args, menu)
+			}
+		},
+	}
+
+	// the first add is skipped, more isn't entered and the second add runs in the main menu
+	err := menu.MenuLoop()
+	if !errors.Is(err, ErrEndOfInput) {
+		t.Errorf("got error %v, expected %v", err, ErrEndOfInput)
+	}
+	if strings.Join(numbers, ",") != "3" {
+		t.Errorf("got numbers %v, expected [3]", numbers)
+	}
+	if !strings.Contains(out.String(), "not allowed") || entered {
+		t.Errorf("expected the middleware error to be printed without entering more, got:\n%s", out.String())
+	}
+}
+
+func TestMiddlewareSkipsSubMenu(t *testing.T) {
+	var out bytes.Buffer
+	var numbers []string
+	menu := scriptTestMenu(&out, &numbers)
+	menu.Session = NewSession(strings.NewReader("more\nadd\n3\n"), &out)
+	entered := false
+	menu.Commands[2].SubMenu.Refresh = func(*Menu) error {
+		entered = true
+		return nil
+	}
+	menu.Middleware = []Middleware{func(next Handler) Handler {
+		return func(command *Command, args *Args, menu *Menu) error {
+			if command.SubMenu != nil {
+				return nil
+			}
+			return next(command, args, menu)
+		}
+	}}
+
+	// more is stopped without an error, so add runs in the main menu
+	menu.MenuLoop()
+	if entered || strings.Join(numbers, ",") != "3" {
+		t.Errorf("got numbers %v with more entered %v, expected [3] without entering it", numbers, entered)
+	}
+}
+
+func TestMiddlewareArgs(t *testing.T) {
+	var got []*Args
+	menu := &Menu{Columns: []MenuColumn{{ColWidth: -5, Type: StringType, Label: "Name"}}}
+	menu.AddCommand(&Command{
+		Name: "scale", ArgSpecs: []ArgSpec{{Name: "factor", Type: IntType, Required: true}},
+		Run: func(args *Args, menu *Menu) error { return nil },
+	})
+	menu.AddCommand(&Command{Name: "plain", Execute: func(args []string, menu *Menu) error { return nil }})
+	menu.Middleware = []Middleware{Before(func(command *Command, args *Args, menu *Menu) error {
+		got = append(got, args)
+		return nil
+	})}
+	menu.Session = NewSession(strings.NewReader("scale 3\nplain a b\n"), &bytes.Buffer{})
+
+	menu.MenuLoop()
+	if len(got) != 2 {
+		t.Fatalf("got %d calls, expected 2", len(got))
+	}
+	if got[0].Int("factor") != 3 {
+		t.Errorf("got factor %v, expected 3", got[0].Values["factor"])
+	}
+	if strings.Join(got[1].Raw, " ") != "plain a b" || len(got[1].Values) != 0 {
+		t.Errorf("got args %+v, expected the raw words without values", got[1])
+	}
+}
+
+func TestOnError(t *testing.T) {
+	var out bytes.Buffer
+	var numbers []string
+	var failed []string
+	menu := scriptTestMenu(&out, &numbers)
+	menu.Session = NewSession(strings.NewReader("fail\nmore\nback\nexit\n"), &out)
+	menu.Commands[2].SubMenu.AddCommand(&Command{Name: "back", Execute: BackFunc})
+	menu.Middleware = []Middleware{OnError(func(command *Command, args *Args, menu *Menu, err error) error {
+		failed = append(failed, command.Name)
+		return errors.New("replaced: " + err.Error())
+	})}
+
+	// going back and exiting aren't failures, and still work
+	err := menu.MenuLoop()
+	if !errors.Is(err, ErrExitProgram) {
+		t.Errorf("got error %v, expected %v", err, ErrExitProgram)
+	}
+	if strings.Join(failed, ",") != "fail" {
+		t.Errorf("got failed commands %v, expected [fail]", failed)
+	}
+	if !strings.Contains(out.String(), "replaced: couldn't fail") {
+		t.Errorf("expected the replaced error to be printed, got:\n%s", out.String())
+	}
+}
+
+func TestRecover(t *testing.T) {
+	var out bytes.Buffer
+	var numbers []string
+	menu := scriptTestMenu(&out, &numbers)
+	menu.AddCommand(&Command{Name: "panic", Execute: func(args []string, menu *Menu) error {
+		panic("something broke")
+	}})
+	menu.Middleware = []Middleware{Recover()}
+	menu.Session = NewSession(strings.NewReader("panic\nadd\n"), &out)
+	menu.Session.OnEOF = EOFBack
+
+	// input ending at the prompt of add still goes back instead of being recovered
+	err := menu.MenuLoop()
+	if err != nil {
+		t.Errorf("got error %v, expected none", err)
+	}
+	if !strings.Contains(out.String(), "command panic failed: something broke") {
+		t.Errorf("expected the panic to be printed, got:\n%s", out.String())
+	}
+}
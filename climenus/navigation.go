@@ -0,0 +1,199 @@
+package climenus
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// input for going back to the previous menu
+const backInput = "back"
+
+// input for going back to the top level menu
+const homeInput = "home"
+
+// input for jumping to a menu by its path from the top level menu, e.g. "goto edit/2"
+const gotoInput = "goto"
+
+// separator between menu names in a goto path
+const pathSeparator = "/"
+
+// separator between menu names in breadcrumbs
+const breadcrumbSeparator = " > "
+
+// breadcrumb label for a top level menu without a Title
+const defaultRootTitle = "Main"
+
+// error returned by an Execute function to navigate the menu tree.
+// Matches ErrBack with errors.Is, so code that only checks for ErrBack
+// still treats it as going back.
+type NavigationError struct {
+	Levels int  // number of menus to go back, at least 1
+	Home   bool // go back to the top level menu instead
+}
+
+// returns an error that makes MenuLoop go back the given number of menus
+func GoBack(levels int) error {
+	return &NavigationError{Levels: levels}
+}
+
+// returns an error that makes MenuLoop go back to its top level menu
+func GoHome() error {
+	return &NavigationError{Home: true}
+}
+
+func (e *NavigationError) Error() string {
+	if e.Home {
+		return "home command issued"
+	}
+	return fmt.Sprintf("%s (%d levels)", BackCommand, e.Levels)
+}
+
+func (e *NavigationError) Is(target error) bool {
+	return target == ErrBack
+}
+
+// applies the navigation to the stack, and returns the number of levels
+// that couldn't be applied because the top level menu was left
+func (e *NavigationError) apply(nav *navigator) int {
+	if e.Home {
+		nav.home()
+		return 0
+	}
+
+	levels := max(e.Levels, 1)
+	leftOver := max(levels-len(nav.stack), 0)
+	nav.truncate(max(len(nav.stack)-levels, 0))
+	return leftOver
+}
+
+// navigation stack used by MenuLoop, the last menu on the stack is the one shown
+type navigator struct {
+	stack []*Menu
+}
+
+// returns the menu currently shown
+func (nav *navigator) current() *Menu {
+	return nav.stack[len(nav.stack)-1]
+}
+
+// returns true once the top level menu has been left
+func (nav *navigator) done() bool {
+	return len(nav.stack) == 0
+}
+
+// enters the submenu of a command from the current menu
+// if the submenu is already open further up the stack, goes back to it instead
+func (nav *navigator) enter(command *Command) {
+	if i := slices.Index(nav.stack, command.SubMenu); i >= 0 {
+		nav.truncate(i + 1)
+		return
+	}
+
+	command.SubMenu.parent = nav.current()
+	command.SubMenu.openedBy = command
+	nav.stack = append(nav.stack, command.SubMenu)
+}
+
+// goes back to the previous menu, leaving MenuLoop if already at the top level menu
+func (nav *navigator) back() {
+	nav.truncate(len(nav.stack) - 1)
+}
+
+// goes back to the top level menu
+func (nav *navigator) home() {
+	nav.truncate(1)
+}
+
+// jumps to the menu at path, given as command names or option numbers
+// separated by "/" starting from the top level menu. Each command on the path
+// must have a SubMenu, their Execute functions are not run.
+// On an invalid path the navigation stack is left unchanged.
+func (nav *navigator) jump(path string) error {
+	previous := nav.stack
+	nav.stack = append([]*Menu{}, nav.stack[0])
+
+	for _, name := range strings.Split(path, pathSeparator) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		menu := nav.current()
+		err := menu.refresh()
+		if err == nil {
+			var command *Command
+			command, err = menu.Command(name)
+			if err == nil && command.SubMenu == nil {
+				err = errors.New("command has no submenu")
+			}
+			if err == nil {
+				nav.enter(command)
+				continue
+			}
+		}
+
+		nav.unlinkMenus(nav.stack, previous)
+		nav.stack = previous
+		return fmt.Errorf("cannot go to %s: %s", path, err.Error())
+	}
+
+	// menus that were left by the jump are no longer part of the tree being shown
+	nav.unlinkMenus(previous, nav.stack)
+
+	return nil
+}
+
+// removes menus from the stack until n menus are left
+func (nav *navigator) truncate(n int) {
+	for len(nav.stack) > n {
+		menu := nav.current()
+		menu.parent, menu.openedBy = nil, nil
+		nav.stack = nav.stack[:len(nav.stack)-1]
+	}
+}
+
+// clears the parent links of any menus that are in menus but not in keep
+func (nav *navigator) unlinkMenus(menus []*Menu, keep []*Menu) {
+	for _, menu := range menus {
+		if !slices.Contains(keep, menu) {
+			menu.parent, menu.openedBy = nil, nil
+		}
+	}
+}
+
+// returns the label for this menu in breadcrumbs: its Title if set, otherwise the
+// name (or description, for unnamed commands) of the command that opened it
+func (menu *Menu) breadcrumbLabel() string {
+	if menu.Title != "" {
+		return menu.Title
+	}
+	if menu.openedBy == nil {
+		return defaultRootTitle
+	}
+	if menu.openedBy.Name != "" {
+		return menu.openedBy.Name
+	}
+	return menu.openedBy.Description
+}
+
+// returns the breadcrumbs showing the path from the top level menu to this menu,
+// e.g. "Main > Edit > Pasta". Only menus entered through a command's SubMenu
+// by MenuLoop are part of the path.
+func (menu *Menu) Breadcrumbs() string {
+	labels := make([]string, 0)
+	for m := menu; m != nil; m = m.parent {
+		labels = append([]string{m.breadcrumbLabel()}, labels...)
+	}
+
+	return strings.Join(labels, breadcrumbSeparator)
+}
+
+// calls the Refresh function for this menu, if it has one
+func (menu *Menu) refresh() error {
+	if menu.Refresh == nil {
+		return nil
+	}
+	return menu.Refresh(menu)
+}
@@ -0,0 +1,181 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// builds a menu tree Main > edit > pasta, where each command records its name
+// in visited when executed
+func navigationTestMenus(visited *[]string) *Menu {
+	record := func(name string) func([]string, *Menu) error {
+		return func(args []string, menu *Menu) error {
+			*visited = append(*visited, name)
+			return nil
+		}
+	}
+
+	pasta := &Menu{Instructions: "pasta menu"}
+	pasta.AddCommand(&Command{Name: "rename", Execute: record("rename")})
+
+	edit := &Menu{Title: "Edit", Instructions: "edit menu"}
+	edit.AddCommand(&Command{Name: "pasta", Execute: record("pasta"), SubMenu: pasta})
+
+	main := &Menu{Instructions: "main menu"}
+	main.AddCommand(&Command{Name: "edit", SubMenu: edit})
+	main.AddCommand(&Command{Name: "exit", Execute: ExitFunc})
+
+	return main
+}
+
+func TestMenuLoopNavigation(t *testing.T) {
+	testCases := []struct {
+		name            string
+		input           string
+		expectedVisited []string
+		expectedOutput  []string
+		expectExit      bool
+	}{
+		{
+			name:            "testEnterAndBack",
+			input:           "edit\npasta\nrename\nback\nback\nback\n",
+			expectedVisited: []string{"pasta", "rename"},
+			expectedOutput:  []string{"Main > Edit\nedit menu", "Main > Edit > pasta\npasta menu"},
+		},
+		{
+			name:            "testHome",
+			input:           "1\n1\nhome\nback\n",
+			expectedVisited: []string{"pasta"},
+			expectedOutput:  []string{"Main > Edit > pasta\npasta menu"},
+		},
+		{
+			name:            "testGoto",
+			input:           "goto edit/1\nrename\nhome\nexit\n",
+			expectedVisited: []string{"rename"},
+			expectedOutput:  []string{"Main > Edit > pasta\npasta menu"},
+			expectExit:      true,
+		},
+		{
+			name:           "testGotoInvalidPath",
+			input:          "goto edit/nothing\nback\n",
+			expectedOutput: []string{"cannot go to edit/nothing: invalid command"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			visited := make([]string, 0)
+			menu := navigationTestMenus(&visited)
+			menu.Session = NewSession(strings.NewReader(tc.input), &out)
+
+			err := menu.MenuLoop()
+			if tc.expectExit && (err == nil || err.Error() != ExitProgram) {
+				t.Errorf("got error %v, expected %v", err, ExitProgram)
+			}
+			if !tc.expectExit && err != nil {
+				t.Errorf("got error %v", err.Error())
+			}
+
+			if strings.Join(visited, ",") != strings.Join(tc.expectedVisited, ",") {
+				t.Errorf("got %v, expected %v", visited, tc.expectedVisited)
+			}
+			for _, expected := range tc.expectedOutput {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("output %q does not contain %q", out.String(), expected)
+				}
+			}
+		})
+	}
+}
+
+func TestBreadcrumbsTopLevel(t *testing.T) {
+	menu := Menu{}
+	if menu.Breadcrumbs() != "Main" {
+		t.Errorf("got %v, expected %v", menu.Breadcrumbs(), "Main")
+	}
+
+	menu.Title = "Recipes"
+	if menu.Breadcrumbs() != "Recipes" {
+		t.Errorf("got %v, expected %v", menu.Breadcrumbs(), "Recipes")
+	}
+}
+
+func TestMenuLoopControlErrors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		execute        func([]string, *Menu) error
+		input          string
+		expectedErr    error
+		expectedOutput string
+	}{
+		{
+			name:           "testWrappedBack",
+			execute:        func([]string, *Menu) error { return fmt.Errorf("saving: %w", ErrBack) },
+			input:          "edit\npasta\nrun\nback\nback\n",
+			expectedOutput: "Main > Edit\nedit menu",
+		},
+		{
+			name:        "testWrappedExit",
+			execute:     func([]string, *Menu) error { return fmt.Errorf("saving: %w", ErrExitProgram) },
+			input:       "edit\npasta\nrun\n",
+			expectedErr: ErrExitProgram,
+		},
+		{
+			name:           "testGoBackLevels",
+			execute:        func([]string, *Menu) error { return GoBack(2) },
+			input:          "edit\npasta\nrun\nback\n",
+			expectedOutput: "Main > Edit > pasta\npasta menu\n\n\n\nmain menu",
+		},
+		{
+			name:           "testGoHome",
+			execute:        func([]string, *Menu) error { return fmt.Errorf("wrapped: %w", GoHome()) },
+			input:          "edit\npasta\nrun\nback\n",
+			expectedOutput: "Main > Edit > pasta\npasta menu\n\n\n\nmain menu",
+		},
+		{
+			name:        "testGoBackPastTopLevel",
+			execute:     func([]string, *Menu) error { return GoBack(5) },
+			input:       "edit\npasta\nrun\n",
+			expectedErr: GoBack(2),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			visited := make([]string, 0)
+			menu := navigationTestMenus(&visited)
+			menu.Session = NewSession(strings.NewReader(tc.input), &out)
+			pasta := menu.Commands[0].SubMenu.Commands[0].SubMenu
+			pasta.AddCommand(&Command{Name: "run", Execute: tc.execute})
+
+			err := menu.MenuLoop()
+			var navErr *NavigationError
+			if errors.As(tc.expectedErr, &navErr) {
+				var gotNavErr *NavigationError
+				if !errors.As(err, &gotNavErr) || *gotNavErr != *navErr {
+					t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+				}
+			} else if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+			}
+
+			if !strings.Contains(out.String(), tc.expectedOutput) {
+				t.Errorf("output %q does not contain %q", out.String(), tc.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestNavigationErrorIsBack(t *testing.T) {
+	if !errors.Is(GoBack(3), ErrBack) {
+		t.Errorf("expected GoBack to match ErrBack")
+	}
+	if errors.Is(GoBack(3), ErrExitProgram) {
+		t.Errorf("expected GoBack not to match ErrExitProgram")
+	}
+}
@@ -0,0 +1,91 @@
+package climenus
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// input for showing the next page of a menu with a PageSize
+const nextPageInput = "next"
+
+// input for showing the previous page of a menu with a PageSize
+const prevPageInput = "prev"
+
+// input for showing a page of a menu with a PageSize by number, e.g. "page 3"
+const pageInput = "page"
+
+// returns the number of pages the menu's commands (that match its filter) are
+// split into, 1 if PageSize isn't set
+func (menu *Menu) PageCount() int {
+	commands := menu.filteredCommands()
+	if menu.PageSize <= 0 || len(commands) == 0 {
+		return 1
+	}
+	return (len(commands) + menu.PageSize - 1) / menu.PageSize
+}
+
+// returns the number of the page shown, starting from 1
+func (menu *Menu) Page() int {
+	// commands may have been removed since the page was chosen
+	return min(menu.page, menu.PageCount()-1) + 1
+}
+
+// sets the page shown by its number, starting from 1
+func (menu *Menu) SetPage(page int) error {
+	if page < 1 || page > menu.PageCount() {
+		return fmt.Errorf("no page %d, pages are 1-%d", page, menu.PageCount())
+	}
+	menu.page = page - 1
+	return nil
+}
+
+// shows the next page of commands
+func (menu *Menu) NextPage() error {
+	if menu.Page() == menu.PageCount() {
+		return errors.New("already on the last page")
+	}
+	return menu.SetPage(menu.Page() + 1)
+}
+
+// shows the previous page of commands
+func (menu *Menu) PrevPage() error {
+	if menu.Page() == 1 {
+		return errors.New("already on the first page")
+	}
+	return menu.SetPage(menu.Page() - 1)
+}
+
+// returns the commands on the page shown, all of the commands if PageSize isn't set.
+// Only commands that match the menu's filter are shown
+func (menu *Menu) pageCommands() []*Command {
+	commands := menu.filteredCommands()
+	if menu.PageSize <= 0 {
+		return commands
+	}
+	start := (menu.Page() - 1) * menu.PageSize
+	end := min(start+menu.PageSize, len(commands))
+	return commands[start:end]
+}
+
+// returns the line shown below the commands of a menu with more than one page,
+// e.g. "showing 21-40 of 312 (page 2 of 16)"
+func (menu *Menu) pageFooter() string {
+	// counts are positions in the filtered commands, not option numbers
+	start := (menu.Page()-1)*menu.PageSize + 1
+	end := start + len(menu.pageCommands()) - 1
+	return fmt.Sprintf("showing %d-%d of %d (page %d of %d)",
+		start, end, len(menu.filteredCommands()), menu.Page(), menu.PageCount())
+}
+
+// shows the page given by number in args[0], for the "page" built-in command
+func (menu *Menu) showPage(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s <number>", pageInput)
+	}
+	page, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid page number %q", args[0])
+	}
+	return menu.SetPage(page)
+}
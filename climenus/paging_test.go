@@ -0,0 +1,122 @@
+package climenus
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// returns a paged menu with the given number of commands, named "cmd1", "cmd2", ...
+func pagingTestMenu(commands int, pageSize int) *Menu {
+	menu := &Menu{PageSize: pageSize, Columns: []MenuColumn{
+		{ColWidth: 3, Type: IntType, Label: "#"},
+		{ColWidth: 6, Type: StringType, Label: "Name"},
+		{ColWidth: 1, Type: StringType, Label: ""},
+	}}
+	for i := 1; i <= commands; i++ {
+		menu.AddCommand(&Command{Name: fmt.Sprintf("cmd%d", i)})
+	}
+	return menu
+}
+
+func TestMenuPages(t *testing.T) {
+	testCases := []struct {
+		name          string
+		commands      int
+		pageSize      int
+		page          int
+		expectedCount int
+		expectedFirst int
+		expectedLast  int
+	}{
+		{name: "testNoPageSize", commands: 7, pageSize: 0, page: 1, expectedCount: 1, expectedFirst: 1, expectedLast: 7},
+		{name: "testFirstPage", commands: 7, pageSize: 3, page: 1, expectedCount: 3, expectedFirst: 1, expectedLast: 3},
+		{name: "testMiddlePage", commands: 7, pageSize: 3, page: 2, expectedCount: 3, expectedFirst: 4, expectedLast: 6},
+		{name: "testPartialLastPage", commands: 7, pageSize: 3, page: 3, expectedCount: 3, expectedFirst: 7, expectedLast: 7},
+		{name: "testExactPages", commands: 6, pageSize: 3, page: 2, expectedCount: 2, expectedFirst: 4, expectedLast: 6},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			menu := pagingTestMenu(tc.commands, tc.pageSize)
+			err := menu.SetPage(tc.page)
+			if err != nil {
+				t.Fatalf("got error %v", err.Error())
+			}
+
+			commands := menu.pageCommands()
+			if menu.PageCount() != tc.expectedCount {
+				t.Errorf("got %v pages, expected %v", menu.PageCount(), tc.expectedCount)
+			}
+			if commands[0].OptionNumber != tc.expectedFirst || commands[len(commands)-1].OptionNumber != tc.expectedLast {
+				t.Errorf("got commands %v-%v, expected %v-%v", commands[0].OptionNumber,
+					commands[len(commands)-1].OptionNumber, tc.expectedFirst, tc.expectedLast)
+			}
+		})
+	}
+}
+
+func TestMenuPageClampedAfterRemovingCommands(t *testing.T) {
+	menu := pagingTestMenu(7, 3)
+	menu.SetPage(3)
+
+	menu.Commands = menu.Commands[:4]
+	if menu.Page() != 2 {
+		t.Errorf("got page %v, expected %v", menu.Page(), 2)
+	}
+}
+
+func TestMenuLoopPaging(t *testing.T) {
+	var out bytes.Buffer
+	menu := pagingTestMenu(7, 3)
+	// option numbers select commands on other pages
+	input := "next\nnext\nnext\nprev\npage 9\npage 1\n7\ncmd2\nback\n"
+	menu.Session = NewSession(strings.NewReader(input), &out)
+
+	issued := make([]string, 0)
+	for _, command := range menu.Commands {
+		command.Execute = func(args []string, m *Menu) error {
+			issued = append(issued, args[0])
+			return nil
+		}
+	}
+
+	err := menu.MenuLoop()
+	if err != nil {
+		t.Errorf("got error %v", err.Error())
+	}
+
+	if strings.Join(issued, ",") != "7,cmd2" {
+		t.Errorf("got %v, expected %v", issued, []string{"7", "cmd2"})
+	}
+	for _, expected := range []string{
+		"showing 1-3 of 7 (page 1 of 3)",
+		"  6   cmd6   \nshowing 4-6 of 7 (page 2 of 3)",
+		"showing 7-7 of 7 (page 3 of 3)",
+		"already on the last page",
+		"no page 9, pages are 1-3",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("output %q does not contain %q", out.String(), expected)
+		}
+	}
+	firstPage, _, _ := strings.Cut(out.String(), "showing")
+	if strings.Contains(firstPage, "cmd4") {
+		t.Errorf("first page %q shows commands from the second page", firstPage)
+	}
+}
+
+func TestPagingBuiltinsOnlyInPagedMenus(t *testing.T) {
+	menu := pagingTestMenu(3, 0)
+	isValid, _ := menu.commandValidator(nextPageInput)
+	if isValid {
+		t.Errorf("got valid input %q for a menu without pages", nextPageInput)
+	}
+
+	menu.PageSize = 2
+	isValid, err := menu.commandValidator(nextPageInput)
+	if !isValid {
+		t.Errorf("got error %v for a paged menu", err)
+	}
+}
@@ -0,0 +1,160 @@
+package climenus
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// function parsing input into a value of type T, returning an error describing
+// why the input isn't valid if it can't be parsed
+type Parser[T any] func(input string) (T, error)
+
+// option for Prompt, see Default, Between and Check
+type PromptOption[T any] func(*promptOptions[T])
+
+// options set for a Prompt call
+type promptOptions[T any] struct {
+	hasDefault   bool
+	defaultValue T
+	checks       []func(T) error
+}
+
+// option for Prompt giving the value returned when nothing is entered,
+// which is shown in brackets after the prompt
+func Default[T any](value T) PromptOption[T] {
+	return func(o *promptOptions[T]) {
+		o.hasDefault = true
+		o.defaultValue = value
+	}
+}
+
+// option for Prompt accepting only values from min to max, inclusive
+func Between[T cmp.Ordered](min T, max T) PromptOption[T] {
+	return Check(func(value T) error {
+		if value < min || value > max {
+			return fmt.Errorf("must be between %v and %v", min, max)
+		}
+		return nil
+	})
+}
+
+// option for Prompt accepting only values check returns no error for.
+// Checks run in order after the input is parsed
+func Check[T any](check func(T) error) PromptOption[T] {
+	return func(o *promptOptions[T]) {
+		o.checks = append(o.checks, check)
+	}
+}
+
+// prompts for input with the menu's session until it is parsed by parse and passes
+// the checks of the options, printing the error for any input that doesn't, and
+// returns the parsed value. A nil menu uses the default session. Returns the zero value
+// and the error if input ends or is stopped first, see Menu.UserInputErr
+func Prompt[T any](menu *Menu, prompt string, parse Parser[T], options ...PromptOption[T]) (T, error) {
+	var opts promptOptions[T]
+	for _, option := range options {
+		option(&opts)
+	}
+	if opts.hasDefault {
+		prompt += fmt.Sprintf(" [%s]", formatDefault(opts.defaultValue))
+	}
+
+	var value T
+	validator := func(input string) (bool, error) {
+		if input == "" && opts.hasDefault {
+			value = opts.defaultValue
+			return true, nil
+		}
+		parsed, err := parse(input)
+		if err != nil {
+			return false, err
+		}
+		for _, check := range opts.checks {
+			if err := check(parsed); err != nil {
+				return false, err
+			}
+		}
+		value = parsed
+		return true, nil
+	}
+
+	if _, err := menu.UserInputErr(prompt, validator); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
+
+// returns a default value as it is shown after a prompt, y or n for bools
+func formatDefault(value interface{}) string {
+	if b, ok := value.(bool); ok {
+		if b {
+			return "y"
+		}
+		return "n"
+	}
+	return fmt.Sprint(value)
+}
+
+// Parser for any text, which is returned as it is
+func ParseText(input string) (string, error) {
+	return input, nil
+}
+
+// Parser for whole numbers
+func ParseInt(input string) (int, error) {
+	n, err := strconv.Atoi(input)
+	if err != nil {
+		return 0, errors.New("must be a whole number")
+	}
+	return n, nil
+}
+
+// Parser for numbers
+func ParseFloat(input string) (float64, error) {
+	f, err := strconv.ParseFloat(input, 64)
+	if err != nil {
+		return 0, errors.New("must be a number")
+	}
+	return f, nil
+}
+
+// Parser for durations, such as "1h30m" or "45s"
+func ParseDuration(input string) (time.Duration, error) {
+	d, err := time.ParseDuration(input)
+	if err != nil {
+		return 0, errors.New("must be a duration, such as 1h30m or 45s")
+	}
+	return d, nil
+}
+
+// Parser for y/yes (true) or n/no (false), ignoring case
+func ParseYesNo(input string) (bool, error) {
+	switch strings.ToLower(input) {
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	}
+	return false, errors.New("must enter either 'Y' or 'N'")
+}
+
+// returns a Parser accepting one of the choices, ignoring case, or its number
+// counting from 1. Returns the choice as it is given
+func ParseChoice(choices ...string) Parser[string] {
+	return func(input string) (string, error) {
+		if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(choices) {
+			return choices[n-1], nil
+		}
+		for _, choice := range choices {
+			if strings.EqualFold(input, choice) {
+				return choice, nil
+			}
+		}
+		return "", fmt.Errorf("must be one of %s, or its number", strings.Join(choices, ", "))
+	}
+}
@@ -0,0 +1,174 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// returns a menu reading the given input
+func promptTestMenu(input string, out *bytes.Buffer) *Menu {
+	menu := &Menu{}
+	menu.Session = NewSession(strings.NewReader(input), out)
+	return menu
+}
+
+func TestPrompt(t *testing.T) {
+	var out bytes.Buffer
+	menu := promptTestMenu("four\n0\n12\n4\n", &out)
+	servings, err := Prompt(menu, "Servings:", ParseInt, Between(1, 10))
+	if servings != 4 || err != nil {
+		t.Errorf("got %v, %v, expected %v", servings, err, 4)
+	}
+
+	expected := "Servings:\nmust be a whole number\n" +
+		"Servings:\nmust be between 1 and 10\n" +
+		"Servings:\nmust be between 1 and 10\n" +
+		"Servings:\n"
+	if out.String() != expected {
+		t.Errorf("got %q, expected %q", out.String(), expected)
+	}
+}
+
+func TestPromptDefault(t *testing.T) {
+	var out bytes.Buffer
+	menu := promptTestMenu("\n\n2.5\n\n", &out)
+
+	if scale, _ := Prompt(menu, "Scale:", ParseFloat, Default(1.0)); scale != 1 {
+		t.Errorf("got %v, expected the default", scale)
+	}
+	if overwrite, _ := Prompt(menu, "Overwrite?", ParseYesNo, Default(false)); overwrite {
+		t.Errorf("got %v, expected the default", overwrite)
+	}
+	if scale, _ := Prompt(menu, "Scale:", ParseFloat, Default(1.0)); scale != 2.5 {
+		t.Errorf("got %v, expected %v", scale, 2.5)
+	}
+	// without a default, empty input is parsed like any other
+	if name, _ := Prompt(menu, "Name:", ParseText); name != "" {
+		t.Errorf("got %q, expected empty input", name)
+	}
+
+	if !strings.Contains(out.String(), "Scale: [1]\n") || !strings.Contains(out.String(), "Overwrite? [n]\n") {
+		t.Errorf("got %q, expected the defaults to be shown", out.String())
+	}
+}
+
+func TestPromptCheck(t *testing.T) {
+	var out bytes.Buffer
+	menu := promptTestMenu("5m\n2h\n20m\n", &out)
+	notTooLong := Check(func(d time.Duration) error {
+		if d > time.Hour {
+			return errors.New("must be at most an hour")
+		}
+		return nil
+	})
+	atLeastTenMinutes := Check(func(d time.Duration) error {
+		if d < 10*time.Minute {
+			return errors.New("must be at least 10 minutes")
+		}
+		return nil
+	})
+
+	d, _ := Prompt(menu, "Time:", ParseDuration, notTooLong, atLeastTenMinutes)
+	if d != 20*time.Minute {
+		t.Errorf("got %v, expected %v", d, 20*time.Minute)
+	}
+	if !strings.Contains(out.String(), "must be at least 10 minutes") || !strings.Contains(out.String(), "must be at most an hour") {
+		t.Errorf("got %q, expected both checks to reject input", out.String())
+	}
+}
+
+func TestPromptEndOfInput(t *testing.T) {
+	menu := promptTestMenu("x\n", &bytes.Buffer{})
+	n, err := Prompt(menu, "Number:", ParseInt)
+	if n != 0 || !errors.Is(err, ErrEndOfInput) {
+		t.Errorf("got %v, %v, expected %v", n, err, ErrEndOfInput)
+	}
+}
+
+func TestPromptStopsCommand(t *testing.T) {
+	testCases := []struct {
+		name        string
+		onEOF       EOFPolicy
+		expectedErr error
+	}{
+		{name: "testEOFError", onEOF: EOFError, expectedErr: ErrEndOfInput},
+		{name: "testEOFBack", onEOF: EOFBack, expectedErr: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var prompts int
+			menu := newTestMenu("ask\n", io.Discard)
+			menu.Session.OnEOF = tc.onEOF
+			menu.AddCommand(&Command{Name: "ask", Execute: func(args []string, menu *Menu) error {
+				// the error is ignored, and input after it is stopped straight away
+				for range 3 {
+					Prompt(menu, "Number:", ParseInt)
+					prompts++
+				}
+				return errors.New("ignored")
+			}})
+			// middleware recovering panics doesn't change how the command is stopped
+			menu.Middleware = []Middleware{Recover()}
+
+			err := menu.MenuLoop()
+			if !errors.Is(err, tc.expectedErr) || (tc.expectedErr == nil && err != nil) {
+				t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+			}
+			if prompts != 3 {
+				t.Errorf("got %d prompts, expected 3", prompts)
+			}
+		})
+	}
+}
+
+func TestParsers(t *testing.T) {
+	meals := ParseChoice("breakfast", "lunch", "dinner")
+	testCases := []struct {
+		name        string
+		parse       func(string) (interface{}, error)
+		input       string
+		expected    interface{}
+		expectedErr string
+	}{
+		{name: "testText", parse: wrapParser(ParseText), input: "pasta", expected: "pasta"},
+		{name: "testInt", parse: wrapParser(ParseInt), input: "-3", expected: -3},
+		{name: "testIntInvalid", parse: wrapParser(ParseInt), input: "3.5", expectedErr: "must be a whole number"},
+		{name: "testFloat", parse: wrapParser(ParseFloat), input: "3.5", expected: 3.5},
+		{name: "testFloatInvalid", parse: wrapParser(ParseFloat), input: "lots", expectedErr: "must be a number"},
+		{name: "testDuration", parse: wrapParser(ParseDuration), input: "1h30m", expected: 90 * time.Minute},
+		{name: "testDurationInvalid", parse: wrapParser(ParseDuration), input: "90", expectedErr: "must be a duration, such as 1h30m or 45s"},
+		{name: "testYes", parse: wrapParser(ParseYesNo), input: "Yes", expected: true},
+		{name: "testNo", parse: wrapParser(ParseYesNo), input: "n", expected: false},
+		{name: "testYesNoInvalid", parse: wrapParser(ParseYesNo), input: "maybe", expectedErr: "must enter either 'Y' or 'N'"},
+		{name: "testChoice", parse: wrapParser(meals), input: "LUNCH", expected: "lunch"},
+		{name: "testChoiceNumber", parse: wrapParser(meals), input: "3", expected: "dinner"},
+		{name: "testChoiceInvalid", parse: wrapParser(meals), input: "4", expectedErr: "must be one of breakfast, lunch, dinner, or its number"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := tc.parse(tc.input)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+				}
+				return
+			}
+			if err != nil || value != tc.expected {
+				t.Errorf("got %v, %v, expected %v", value, err, tc.expected)
+			}
+		})
+	}
+}
+
+// returns the parser with its value as an interface{}, so parsers of different types can be tested together
+func wrapParser[T any](parse Parser[T]) func(string) (interface{}, error) {
+	return func(input string) (interface{}, error) {
+		return parse(input)
+	}
+}
@@ -0,0 +1,102 @@
+package climenus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// error wrapped by a ScriptError when a script ends while a prompt is waiting for input
+var ErrScriptEnded = errors.New("script ended while waiting for input")
+
+// error for input that isn't accepted while a script is run, see Session.Script
+type ScriptError struct {
+	Line   int    // line of the script with the input, or the line after the last if it ended
+	Prompt string // prompt the input was entered at, empty for the commands of a menu
+	Input  string // input that wasn't accepted
+	Err    error  // why the input wasn't accepted
+}
+
+func (e *ScriptError) Error() string {
+	if errors.Is(e.Err, ErrScriptEnded) {
+		if e.Prompt == "" {
+			return fmt.Sprintf("script line %d: %v", e.Line, e.Err)
+		}
+		return fmt.Sprintf("script line %d: %v at prompt %q", e.Line, e.Err, e.Prompt)
+	}
+	if e.Prompt == "" {
+		return fmt.Sprintf("script line %d: %q: %v", e.Line, e.Input, e.Err)
+	}
+	return fmt.Sprintf("script line %d: %q at prompt %q: %v", e.Line, e.Input, e.Prompt, e.Err)
+}
+
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
+// error for input rejected by a validator without saying why
+var errInvalidInput = errors.New("invalid input")
+
+// returns whether a line of the script the session runs is skipped, which blank lines
+// and comments starting with # are where a menu is waiting for a command
+func (s *Session) skipsScriptLine(line string) bool {
+	return s.Script && s.awaitingCommand && (line == "" || strings.HasPrefix(line, "#"))
+}
+
+// returns the error input is stopped with when the script the session runs ends at
+// the prompt. ErrScriptEnded itself is returned while a menu is waiting for a command,
+// where the script may end, which MenuLoop stops without an error for
+func (s *Session) scriptEnded(prompt string) error {
+	if s.awaitingCommand {
+		return ErrScriptEnded
+	}
+	return s.scriptError(prompt, "", ErrScriptEnded)
+}
+
+// returns a ScriptError for input entered on the last line read by the session
+func (s *Session) scriptError(prompt string, input string, err error) *ScriptError {
+	var scriptErr *ScriptError
+	if errors.As(err, &scriptErr) {
+		return scriptErr
+	}
+	if err == nil {
+		err = errInvalidInput
+	}
+	line := s.line
+	if errors.Is(err, ErrScriptEnded) {
+		line++
+	}
+	return &ScriptError{Line: line, Prompt: prompt, Input: input, Err: err}
+}
+
+// runs the menu's MenuLoop with input read from script, one input per line, including
+// the answers to prompts shown by commands. Blank lines and comments starting with #
+// are skipped where a command is expected, while answers are read as they are (e.g.
+// a blank line accepting a default). Output is written to the menu's output.
+// Returns nil if the script ends while the menu is waiting for a command, ErrExitProgram
+// if it exits the program, and a *ScriptError for the first input that isn't accepted
+// (an unknown command, input rejected by a validator or a command returning an error)
+// or if it ends while a prompt is waiting for input
+func RunScript(menu *Menu, script io.Reader) error {
+	previous := menu.Session
+	current := menu.session()
+	session := NewSession(script, current.Out)
+	session.Color = current.Color
+	session.Script = true
+
+	menu.Session = session
+	defer func() { menu.Session = previous }()
+	return menu.MenuLoop()
+}
+
+// runs the script in the named file with RunScript
+func RunScriptFile(menu *Menu, name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return RunScript(menu, file)
+}
@@ -0,0 +1,175 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// returns a menu with a command that asks for a number, and a submenu
+func scriptTestMenu(out *bytes.Buffer, numbers *[]string) *Menu {
+	menu := &Menu{Columns: []MenuColumn{
+		{ColWidth: 2, Type: IntType, Label: "#"},
+		{ColWidth: -5, Type: StringType, Label: "Name"},
+	}}
+	menu.Session = NewSession(strings.NewReader(""), out)
+
+	numberValidator := func(s string) (bool, error) {
+		if strings.Trim(s, "0123456789") != "" || s == "" {
+			return false, errors.New("must be a number")
+		}
+		return true, nil
+	}
+	menu.AddCommand(&Command{Name: "add", Execute: func(args []string, menu *Menu) error {
+		number, err := menu.UserInputErr("Enter a number:", numberValidator)
+		if err != nil {
+			return err
+		}
+		*numbers = append(*numbers, number)
+		return nil
+	}})
+	menu.AddCommand(&Command{Name: "fail", Execute: func(args []string, menu *Menu) error {
+		return errors.New("couldn't fail")
+	}})
+
+	subMenu := &Menu{Columns: menu.Columns}
+	subMenu.AddCommand(&Command{Name: "add", Execute: menu.Commands[0].Execute})
+	menu.AddCommand(&Command{Name: "more", SubMenu: subMenu})
+	menu.AddCommand(&Command{Name: "exit", Execute: ExitFunc})
+	return menu
+}
+
+func TestRunScript(t *testing.T) {
+	testCases := []struct {
+		name        string
+		script      string
+		expectedErr string
+		numbers     []string
+	}{
+		{name: "testEndsAtMenu", script: "add\n12\nmore\nadd\n3\n", numbers: []string{"12", "3"}},
+		{
+			name:    "testBlankLinesAndComments",
+			script:  "# add a number\nadd\n12\n\n  \n# then one in the submenu\nmore\nadd\n3\n",
+			numbers: []string{"12", "3"},
+		},
+		{
+			name:        "testLineAfterBlankLines",
+			script:      "add\n1\n\n# comment\nsubtract\n",
+			expectedErr: `script line 5: "subtract": not a valid command`,
+			numbers:     []string{"1"},
+		},
+		{name: "testExit", script: "add\n1\nexit\nadd\n2\n", expectedErr: ExitProgram, numbers: []string{"1"}},
+		{
+			name:        "testInvalidInput",
+			script:      "add\n1\nadd\nten\n10\n",
+			expectedErr: `script line 4: "ten" at prompt "Enter a number:": must be a number`,
+			numbers:     []string{"1"},
+		},
+		{
+			name:        "testUnknownCommand",
+			script:      "more\nback\nsubtract\n",
+			expectedErr: `script line 3: "subtract": not a valid command`,
+		},
+		{
+			name:        "testCommandError",
+			script:      "add\n1\nfail\nadd\n2\n",
+			expectedErr: `script line 3: "fail": couldn't fail`,
+			numbers:     []string{"1"},
+		},
+		{
+			name:        "testEndsAtPrompt",
+			script:      "more\nadd",
+			expectedErr: `script line 3: script ended while waiting for input at prompt "Enter a number:"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			var numbers []string
+			menu := scriptTestMenu(&out, &numbers)
+			err := RunScript(menu, strings.NewReader(tc.script))
+
+			if tc.expectedErr == "" && err != nil {
+				t.Errorf("got error %v, expected none", err)
+			} else if tc.expectedErr != "" && (err == nil || !strings.HasPrefix(err.Error(), tc.expectedErr)) {
+				t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+			}
+			if strings.Join(numbers, ",") != strings.Join(tc.numbers, ",") {
+				t.Errorf("got numbers %v, expected %v", numbers, tc.numbers)
+			}
+		})
+	}
+}
+
+func TestScriptError(t *testing.T) {
+	var out bytes.Buffer
+	var numbers []string
+	menu := scriptTestMenu(&out, &numbers)
+	session := menu.Session
+
+	err := RunScript(menu, strings.NewReader("add\n\n"))
+	var scriptErr *ScriptError
+	if !errors.As(err, &scriptErr) {
+		t.Fatalf("got error %v, expected a *ScriptError", err)
+	}
+	if scriptErr.Line != 2 || scriptErr.Input != "" || scriptErr.Prompt != "Enter a number:" {
+		t.Errorf("got %+v, expected the error for line 2", scriptErr)
+	}
+
+	// the menu's session is restored after the script
+	if menu.Session != session {
+		t.Errorf("menu session wasn't restored")
+	}
+	// output goes to the menu's output, including the rejected input's error
+	if !strings.Contains(out.String(), "must be a number") {
+		t.Errorf("got %q, expected the validation error to be printed", out.String())
+	}
+}
+
+func TestScriptEndsInRecoveringCommand(t *testing.T) {
+	var out bytes.Buffer
+	var numbers []string
+	menu := scriptTestMenu(&out, &numbers)
+	recovered := false
+	menu.AddCommand(&Command{Name: "guarded", Execute: func(args []string, menu *Menu) error {
+		defer func() {
+			if recover() != nil {
+				recovered = true
+			}
+		}()
+		return menu.Commands[0].Execute(args, menu)
+	}})
+
+	// the app recovering panics doesn't change how the script is stopped
+	err := RunScript(menu, strings.NewReader("guarded\n"))
+	var scriptErr *ScriptError
+	if !errors.As(err, &scriptErr) || !errors.Is(err, ErrScriptEnded) || recovered {
+		t.Errorf("got error %v with a panic recovered %v, expected the script to end at the prompt", err, recovered)
+	}
+}
+
+func TestRunScriptFile(t *testing.T) {
+	var out bytes.Buffer
+	var numbers []string
+	menu := scriptTestMenu(&out, &numbers)
+
+	name := filepath.Join(t.TempDir(), "script.txt")
+	if err := os.WriteFile(name, []byte("add\n7\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RunScriptFile(menu, name); err != nil {
+		t.Errorf("got error %v", err)
+	}
+	if strings.Join(numbers, ",") != "7" {
+		t.Errorf("got numbers %v, expected [7]", numbers)
+	}
+
+	err := RunScriptFile(menu, filepath.Join(t.TempDir(), "missing.txt"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got error %v, expected a missing file error", err)
+	}
+}
@@ -0,0 +1,328 @@
+package climenus
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// environment variable giving the terminal width when it can't be read from the terminal
+const columnsEnv = "COLUMNS"
+
+// terminal width used when it can't be read from the terminal or COLUMNS
+const defaultTerminalWidth = 80
+
+// struct representing the input and output streams used by menus and prompts.
+// A single Session should be shared by everything reading from the same input,
+// since the scanner it holds may buffer input beyond the current line.
+type Session struct {
+	In  io.Reader // stream that user input is read from
+	Out io.Writer // stream that menus, prompts and messages are written to
+	// line editor used to read input when In is a terminal, adding line editing,
+	// history and tab completion. Plain lines are read if nil or In isn't a terminal
+	Editor *LineEditor
+	// whether menu themes are applied to Out, decided from Out and NO_COLOR if ColorAuto
+	Color ColorMode
+	// whether In is a script of inputs rather than a user. Instead of prompting again,
+	// the first invalid input stops MenuLoop with a *ScriptError, see RunScript
+	Script bool
+	// width menus are laid out for, overriding the terminal width if set
+	Width int
+	// max time to wait for a line of input in MenuLoop and the Context functions,
+	// which stop with ErrIdleTimeout once it passes. Input is waited for forever if 0
+	IdleTimeout time.Duration
+	// what MenuLoop does when input ends, e.g. at the end of a file or when Ctrl+D is
+	// pressed in the line editor. Returns ErrEndOfInput by default
+	OnEOF EOFPolicy
+
+	scanner *bufio.Scanner // line scanner over In, created on first read
+	reader  *bufio.Reader  // buffered reader over In used by Editor, created on first read
+	line    int            // number of lines read from In, for script errors
+
+	awaitingCommand bool      // whether a command for a menu is being read, where a script may end
+	recorder        *Recorder // recorder of the session's transcript, nil if not recording
+	replay          bool      // whether a transcript is replayed, which ends like a script
+	err             error     // error that ended the last input, see Err
+	stop            error     // error that stopped input in the command being run, see userInput
+
+	loopCtx context.Context // context of the MenuLoop taking input, nil outside of one
+	pending chan lineResult // line being read in the background after a read was stopped
+	mu      sync.Mutex      // guards restore, interrupts and hooks, used by goroutines
+	restore func()          // restores the terminal while the line editor reads in raw mode
+
+	interrupts chan os.Signal // signals trapped by TrapSignals, nil if they aren't trapped
+	hooks      []func()       // functions run on shutdown, see OnShutdown
+	shutdown   sync.Once      // runs the hooks only once
+}
+
+// setting for whether a session's output is styled with colors
+type ColorMode int
+
+const (
+	ColorAuto   ColorMode = iota // colors if the output is a terminal and NO_COLOR isn't set
+	ColorAlways                  // always use colors
+	ColorNever                   // never use colors
+)
+
+// session used by UserInput, UserInputLoop and any menu without its own Session
+var defaultSession = NewSession(os.Stdin, os.Stdout)
+
+// creates a new session reading input from in and writing output to out
+func NewSession(in io.Reader, out io.Writer) *Session {
+	return &Session{In: in, Out: out}
+}
+
+// returns the session used when no session is set on a menu
+func DefaultSession() *Session {
+	return defaultSession
+}
+
+// replaces the session used by UserInput, UserInputLoop and any menu
+// without its own Session, e.g. to drive a whole program from a test
+func SetDefaultSession(session *Session) {
+	defaultSession = session
+}
+
+// returns the width in columns that menus written to the session are laid out for:
+// Width if set, or the width of the terminal Out is connected to, or else the COLUMNS
+// environment variable, or else 80. Read again for each render, so menus follow
+// window resizes.
+func (s *Session) TerminalWidth() int {
+	if s.Width > 0 {
+		return s.Width
+	}
+	if file, ok := s.outFile(); ok {
+		if width, err := terminalWidth(file); err == nil {
+			return width
+		}
+	}
+	if width, err := strconv.Atoi(os.Getenv(columnsEnv)); err == nil && width > 0 {
+		return width
+	}
+	return defaultTerminalWidth
+}
+
+// reads the next line of input from the session, with surrounding whitespace trimmed.
+// complete gives tab completions when the line editor is used, and may be nil.
+// Returns ErrEndOfInput if there was no more input to read.
+func (s *Session) readLine(complete func(string) []string) (string, error) {
+	line, edited, err := s.editLine(complete)
+	if !edited {
+		if s.scanner == nil {
+			s.scanner = bufio.NewScanner(s.In)
+		}
+		if !s.scanner.Scan() {
+			return "", endOfInput(s.scanner.Err())
+		}
+		line = s.scanner.Text()
+	} else if err != nil {
+		return "", err
+	}
+
+	s.line++
+	line = strings.TrimSpace(line)
+	if s.skipsScriptLine(line) {
+		return s.readLine(complete)
+	}
+	if s.recorder != nil {
+		s.recorder.recordInput(line)
+	}
+	return line, nil
+}
+
+// reads a line with the line editor if the session has one and In is a terminal,
+// returns false if the line should be read as a plain line instead. Input ending
+// (e.g. Ctrl+D on an empty line) is returned as ErrEndOfInput, a line cut short by
+// the end of input is still returned. Ctrl+C returns ErrInterrupted, after sending
+// SIGINT if signals aren't trapped, see TrapSignals
+func (s *Session) editLine(complete func(string) []string) (string, bool, error) {
+	file, ok := s.In.(*os.File)
+	if s.Editor == nil || !ok || !isTerminal(file) {
+		return "", false, nil
+	}
+
+	restore, err := makeRaw(file)
+	if err != nil {
+		return "", false, nil
+	}
+	s.setRestore(restore)
+	defer s.restoreTerminal()
+
+	if s.reader == nil {
+		s.reader = bufio.NewReader(s.In)
+	}
+	// the editor's redrawing isn't part of a recorded transcript, only the line read
+	out := s.Out
+	if s.recorder != nil {
+		out = s.recorder.out
+	}
+	line, err := s.Editor.edit(s.reader, out, complete)
+	if errors.Is(err, ErrInterrupted) {
+		// Ctrl+C is read as a key, so the terminal is restored before it is sent as
+		// a signal, which ends the program unless signals are trapped
+		s.restoreTerminal()
+		if s.trappedSignals() == nil {
+			raiseInterrupt()
+		}
+		return "", true, err
+	}
+	if err != nil && line == "" {
+		return "", true, endOfInput(err)
+	}
+	return line, true, nil
+}
+
+// prints the prompt and reads input from the session until the validator accepts it,
+// printing any validation error before prompting again. Returns the accepted input,
+// or "" once input has ended or is stopped, see Err and UserInputErr.
+func (s *Session) UserInput(prompt string, validator func(string) (bool, error)) string {
+	input, _ := s.userInput(prompt, validator, nil, PlainTheme)
+	return input
+}
+
+// takes validated input like UserInput, returning the error if input ends or is
+// stopped first: ErrEndOfInput once input has ended (wrapping the error if reading
+// failed), ErrInterrupted or ErrTerminated for a trapped signal, or the error of the
+// MenuLoop's context. Inside a MenuLoop, the command taking the input is stopped once
+// it returns, see Menu.UserInputErr
+func (s *Session) UserInputErr(prompt string, validator func(string) (bool, error)) (string, error) {
+	return s.userInput(prompt, validator, nil, PlainTheme)
+}
+
+// takes validated input like UserInputErr, with tab completion from complete if the
+// line editor is used, and the prompt and errors styled by theme. Inside a MenuLoop,
+// an error stopping the input is kept until the command taking it returns, so any more
+// input the command takes returns it straight away and MenuLoop can handle it, see
+// Menu.runCommand. The error is kept for Err too
+func (s *Session) userInput(
+	prompt string, validator func(string) (bool, error), complete func(string) []string, theme *Theme,
+) (string, error) {
+	if s.stop != nil {
+		s.err = s.stop
+		return "", s.stop
+	}
+	input, err := s.userInputContext(s.loopCtx, prompt, validator, complete, theme)
+	s.err = err
+	if s.loopCtx != nil && err != nil {
+		s.stop = err
+	}
+	return input, err
+}
+
+// returns the error that ended or stopped the last input taken with UserInput or
+// UserInputLoop, see UserInputErr. Returns nil if the last input was read
+func (s *Session) Err() error {
+	return s.err
+}
+
+// takes validated input like userInput, returning an error if ctx is done first.
+// A nil ctx waits for input forever
+func (s *Session) userInputContext(
+	ctx context.Context, prompt string, validator func(string) (bool, error),
+	complete func(string) []string, theme *Theme,
+) (string, error) {
+	if ctx != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	isValid := false
+	err := error(nil)
+	input := ""
+	for !isValid {
+		fmt.Fprintln(s.Out, theme.Prompt.Render(prompt))
+		line, readErr := s.readLineContext(ctx, complete)
+		if errors.Is(readErr, ErrEndOfInput) && (s.Script || s.replay) {
+			readErr = s.scriptEnded(prompt)
+		}
+		if readErr != nil {
+			return "", readErr
+		}
+		input = line
+		isValid, err = validator(input)
+		if err != nil {
+			fmt.Fprintln(s.Out, theme.Error.Render(err.Error()))
+		}
+		if !isValid && s.Script {
+			return "", s.scriptError(prompt, input, err)
+		}
+	}
+
+	return input, nil
+}
+
+// repeatedly takes validated input from the session until exitLoop is entered,
+// and returns the inputs entered before it. Stops early if input ends, see Err
+func (s *Session) UserInputLoop(prompt string, exitLoop string, validator func(string) (bool, error)) []string {
+	input := ""
+	inputStrings := make([]string, 0)
+
+	for input != exitLoop {
+		input = s.UserInput(prompt, validator)
+		if s.err != nil {
+			break
+		}
+		if input != exitLoop {
+			inputStrings = append(inputStrings, input)
+		}
+	}
+
+	return inputStrings
+}
+
+// returns the session for this menu, falling back to the session of the menu
+// it was opened from as a SubMenu, and then to the default session
+func (menu *Menu) session() *Session {
+	for m := menu; m != nil; m = m.parent {
+		if m.Session != nil {
+			return m.Session
+		}
+	}
+	return defaultSession
+}
+
+// returns the writer this menu prints its output to
+func (menu *Menu) Out() io.Writer {
+	return menu.session().Out
+}
+
+// takes validated user input using this menu's session, see Session.UserInput.
+// The prompt and errors are styled by the menu's theme
+func (menu *Menu) UserInput(prompt string, validator func(string) (bool, error)) string {
+	input, _ := menu.UserInputErr(prompt, validator)
+	return input
+}
+
+// takes validated user input like UserInput, returning the error if input ends or is
+// stopped first, see Session.UserInputErr. Inside a MenuLoop, the command taking the
+// input is stopped once it returns, whatever it returns: an interrupt cancels it, the
+// end of input is handled by the session's OnEOF, SIGTERM exits, and anything else
+// (e.g. a cancelled context) stops the loop. Until then, any more input the command
+// takes returns the same error straight away
+func (menu *Menu) UserInputErr(prompt string, validator func(string) (bool, error)) (string, error) {
+	return menu.session().userInput(prompt, validator, nil, menu.theme())
+}
+
+// takes a list of validated user inputs using this menu's session, see Session.UserInputLoop
+func (menu *Menu) UserInputLoop(prompt string, exitLoop string, validator func(string) (bool, error)) []string {
+	input := ""
+	inputStrings := make([]string, 0)
+
+	for input != exitLoop {
+		input = menu.UserInput(prompt, validator)
+		if menu.session().err != nil {
+			break
+		}
+		if input != exitLoop {
+			inputStrings = append(inputStrings, input)
+		}
+	}
+
+	return inputStrings
+}
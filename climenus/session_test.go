@@ -0,0 +1,80 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSessionUserInput(t *testing.T) {
+	var out bytes.Buffer
+	session := NewSession(strings.NewReader("bad\n  good  \n"), &out)
+
+	validator := func(s string) (bool, error) {
+		if s != "good" {
+			return false, errors.New("input must be good")
+		}
+		return true, nil
+	}
+
+	input := session.UserInput("enter input:", validator)
+	if input != "good" {
+		t.Errorf("got %v, expected %v", input, "good")
+	}
+
+	expectedOut := "enter input:\ninput must be good\nenter input:\n"
+	if out.String() != expectedOut {
+		t.Errorf("got %q, expected %q", out.String(), expectedOut)
+	}
+}
+
+func TestSessionUserInputLoop(t *testing.T) {
+	var out bytes.Buffer
+	session := NewSession(strings.NewReader("a\nb\ndone\nleftover\n"), &out)
+	acceptAll := func(string) (bool, error) { return true, nil }
+
+	inputs := session.UserInputLoop("", "done", acceptAll)
+	if strings.Join(inputs, ",") != "a,b" {
+		t.Errorf("got %v, expected %v", inputs, []string{"a", "b"})
+	}
+
+	// input after the exit string must still be available to the next read
+	next := session.UserInput("", acceptAll)
+	if next != "leftover" {
+		t.Errorf("got %v, expected %v", next, "leftover")
+	}
+}
+
+func TestMenuLoopSession(t *testing.T) {
+	var out bytes.Buffer
+	var menu Menu
+	// "1" runs the command, whose nested prompt consumes "answer", then "back" exits
+	menu.Session = NewSession(strings.NewReader("1\nanswer\nback\n"), &out)
+	menu.Instructions = "test instructions"
+	menu.Columns = []MenuColumn{
+		{ColWidth: 2, Type: StringType, Label: "#"},
+		{ColWidth: -5, Type: StringType, Label: "Name"},
+	}
+
+	executed := 0
+	menu.AddCommand(&Command{Name: "run", Execute: func(args []string, m *Menu) error {
+		executed++
+		m.UserInput("nested prompt", func(string) (bool, error) { return true, nil })
+		return nil
+	}})
+	menu.AddCommand(&Command{Name: "back", Execute: BackFunc})
+
+	err := menu.MenuLoop()
+	if err != nil {
+		t.Errorf("got error %v", err.Error())
+	}
+	if executed != 1 {
+		t.Errorf("got %v executions, expected %v", executed, 1)
+	}
+	for _, expected := range []string{"test instructions", " 1 run", "nested prompt"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("output %q does not contain %q", out.String(), expected)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package climenus
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// error for input stopped by an interrupt (Ctrl+C), see TrapSignals
+var ErrInterrupted = errors.New("interrupted")
+
+// error for input stopped by SIGTERM while signals are trapped, see TrapSignals
+var ErrTerminated = errors.New("terminated")
+
+// prompt asking whether to exit when the top level menu is interrupted
+const confirmExitPrompt = "Exit the program? (y/n)"
+
+// message printed when an interrupt stops a command waiting for input
+const cancelledMessage = "Cancelled."
+
+// exit status of a program ended by a second signal, as shells use for SIGINT
+const interruptedExitCode = 130
+
+// exits the program, replaced in tests
+var exit = os.Exit
+
+// traps SIGINT (Ctrl+C) and SIGTERM until stop is called, so they no longer kill the
+// program. In a MenuLoop, an interrupt cancels the command waiting for input, or goes
+// back to the previous menu at a menu's prompt. At the top level menu, exiting is
+// confirmed first, and a second interrupt while confirming exits as well. SIGTERM exits
+// straight away. Exiting runs the shutdown hooks, see OnShutdown, and returns an error
+// matching ErrExitProgram and ErrInterrupted or ErrTerminated. If a signal arrives
+// before the last one is handled (e.g. while a command is busy), the hooks are run and
+// the program exits. Outside a MenuLoop, UserInputErr returns the error, see Session.Err.
+func (s *Session) TrapSignals() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	interrupts := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	s.setInterrupts(interrupts)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				select {
+				case interrupts <- sig:
+				default:
+					// the last signal hasn't been handled, so the program isn't responding
+					s.Shutdown()
+					exit(interruptedExitCode)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+		s.setInterrupts(nil)
+	}
+}
+
+// registers a function run when the program exits because of a trapped signal, e.g.
+// to save changes that would be lost. Hooks are run in the order they are registered
+func (s *Session) OnShutdown(hook func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook)
+}
+
+// runs the shutdown hooks registered with OnShutdown, only the first time it is called.
+// Called when a trapped signal exits the program, and may be called by the program
+// to run the hooks when exiting otherwise
+func (s *Session) Shutdown() {
+	s.shutdown.Do(func() {
+		s.mu.Lock()
+		hooks := s.hooks
+		s.mu.Unlock()
+		for _, hook := range hooks {
+			hook()
+		}
+	})
+}
+
+// sets the channel trapped signals are passed on to, nil when they aren't trapped
+func (s *Session) setInterrupts(interrupts chan os.Signal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.interrupts = interrupts
+}
+
+// returns the channel trapped signals are passed on to, nil if they aren't trapped
+func (s *Session) trappedSignals() chan os.Signal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interrupts
+}
+
+// returns the error a read stopped by a trapped signal returns
+func signalError(sig os.Signal) error {
+	if sig == os.Interrupt {
+		return ErrInterrupted
+	}
+	return ErrTerminated
+}
+
+// runs the shutdown hooks and returns the error MenuLoop exits with for a signal
+func (s *Session) exitOnSignal(err error) error {
+	s.Shutdown()
+	return fmt.Errorf("%w: %w", ErrExitProgram, err)
+}
+
+// asks whether to exit after the top level menu is interrupted. Another interrupt,
+// or input ending, while asking exits too
+func (menu *Menu) confirmExit() bool {
+	session := menu.session()
+	confirmed := false
+	validator := func(input string) (bool, error) {
+		var err error
+		confirmed, err = ParseYesNo(input)
+		return err == nil, err
+	}
+	_, err := session.userInputContext(session.loopCtx, confirmExitPrompt, validator, nil, menu.theme())
+	return confirmed || err != nil
+}
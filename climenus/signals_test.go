@@ -0,0 +1,228 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// input lines that send a signal instead of being read
+const (
+	sendInterrupt = "^C"
+	sendTerminate = "^TERM"
+)
+
+// reader returning one line per read, which sends a signal for sendInterrupt and
+// sendTerminate lines, as if it arrived while the input was waited for. The next line
+// isn't returned until something is printed, so the signal is handled first. After the
+// last signal stops the loop, the read is left waiting for the rest of the test.
+type signallingReader struct {
+	lines      []string
+	interrupts chan<- os.Signal
+	printed    <-chan struct{}
+}
+
+func (r *signallingReader) Read(p []byte) (int, error) {
+	for len(r.lines) > 0 {
+		line := r.lines[0]
+		r.lines = r.lines[1:]
+		if line != sendInterrupt && line != sendTerminate {
+			return copy(p, line+"\n"), nil
+		}
+
+		// the prompt printed before this read isn't waited for
+		select {
+		case <-r.printed:
+		default:
+		}
+		if line == sendInterrupt {
+			r.interrupts <- os.Interrupt
+		} else {
+			r.interrupts <- syscall.SIGTERM
+		}
+		<-r.printed
+	}
+	return 0, io.EOF
+}
+
+// writer signalling each time something is written to it
+type notifyingWriter struct {
+	bytes.Buffer
+	printed chan struct{}
+}
+
+func (w *notifyingWriter) Write(p []byte) (int, error) {
+	select {
+	case w.printed <- struct{}{}:
+	default:
+	}
+	return w.Buffer.Write(p)
+}
+
+// returns a session reading lines with a signallingReader, as if signals were trapped
+func signallingSession(lines ...string) (*Session, *notifyingWriter) {
+	out := &notifyingWriter{printed: make(chan struct{}, 1)}
+	interrupts := make(chan os.Signal, 1)
+	session := NewSession(&signallingReader{lines: lines, interrupts: interrupts, printed: out.printed}, out)
+	session.interrupts = interrupts
+	return session, out
+}
+
+func TestMenuLoopSignals(t *testing.T) {
+	testCases := []struct {
+		name        string
+		lines       []string
+		expectedErr error
+		signalErr   error // error the exit is expected to be caused by, nil if not a signal
+		numbers     []string
+		output      string
+	}{
+		{
+			name: "testCancelPrompt", lines: []string{"add", sendInterrupt, "add", "4"},
+			expectedErr: ErrEndOfInput, numbers: []string{"4"}, output: cancelledMessage,
+		},
+		{name: "testBackFromSubMenu", lines: []string{"more", sendInterrupt, "exit"}, expectedErr: ErrExitProgram},
+		{
+			name: "testConfirmExit", lines: []string{sendInterrupt, "y"},
+			expectedErr: ErrExitProgram, signalErr: ErrInterrupted, output: confirmExitPrompt,
+		},
+		{
+			name: "testDeclineExit", lines: []string{sendInterrupt, "n", "add", "6"},
+			expectedErr: ErrEndOfInput, numbers: []string{"6"},
+		},
+		{
+			name: "testInterruptWhileConfirming", lines: []string{sendInterrupt, sendInterrupt},
+			expectedErr: ErrExitProgram, signalErr: ErrInterrupted,
+		},
+		{
+			name: "testTerminateAtPrompt", lines: []string{"add", sendTerminate},
+			expectedErr: ErrExitProgram, signalErr: ErrTerminated,
+		},
+		{
+			name: "testTerminateAtMenu", lines: []string{"more", sendTerminate},
+			expectedErr: ErrExitProgram, signalErr: ErrTerminated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var numbers []string
+			session, out := signallingSession(tc.lines...)
+			menu := scriptTestMenu(&out.Buffer, &numbers)
+			menu.Session = session
+			hookRuns := 0
+			session.OnShutdown(func() { hookRuns++ })
+
+			err := menu.MenuLoop()
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+			}
+			if tc.signalErr != nil && !errors.Is(err, tc.signalErr) {
+				t.Errorf("got error %v, expected it to match %v", err, tc.signalErr)
+			}
+			if tc.signalErr == nil && hookRuns != 0 || tc.signalErr != nil && hookRuns != 1 {
+				t.Errorf("shutdown hooks ran %d times for error %v", hookRuns, err)
+			}
+			if strings.Join(numbers, ",") != strings.Join(tc.numbers, ",") {
+				t.Errorf("got numbers %v, expected %v", numbers, tc.numbers)
+			}
+			if !strings.Contains(out.String(), tc.output) {
+				t.Errorf("expected output to contain %q, got:\n%s", tc.output, out.String())
+			}
+		})
+	}
+}
+
+func TestUserInputInterrupted(t *testing.T) {
+	session, _ := signallingSession(sendInterrupt, "a", "b", "done")
+	inputs := session.UserInputLoop("Input:", "done", func(string) (bool, error) { return true, nil })
+	if len(inputs) != 0 || !errors.Is(session.Err(), ErrInterrupted) {
+		t.Errorf("got inputs %v and error %v, expected none and %v", inputs, session.Err(), ErrInterrupted)
+	}
+
+	// the input after the interrupt is read by the next prompt
+	if input := session.UserInput("Input:", func(string) (bool, error) { return true, nil }); input != "a" {
+		t.Errorf("got %q, expected %q", input, "a")
+	}
+}
+
+func TestShutdownRunsHooksOnce(t *testing.T) {
+	session := NewSession(strings.NewReader(""), io.Discard)
+	var order []string
+	session.OnShutdown(func() { order = append(order, "first") })
+	session.OnShutdown(func() { order = append(order, "second") })
+
+	session.Shutdown()
+	session.Shutdown()
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("got hooks run %v, expected first,second once", order)
+	}
+}
+
+// sends sig to the test process
+func signalSelf(t *testing.T, sig os.Signal) {
+	process, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = process.Signal(sig)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTrapSignals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals can't be sent to the test process on windows")
+	}
+	in, writer := io.Pipe()
+	defer writer.Close()
+	session := NewSession(in, io.Discard)
+	stop := session.TrapSignals()
+	defer stop()
+
+	signalSelf(t, os.Interrupt)
+	input := session.UserInput("Input:", func(string) (bool, error) { return true, nil })
+	if input != "" || !errors.Is(session.Err(), ErrInterrupted) {
+		t.Errorf("got %q and error %v, expected %v", input, session.Err(), ErrInterrupted)
+	}
+}
+
+func TestTrapSignalsUnhandled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals can't be sent to the test process on windows")
+	}
+	exitCodes := make(chan int, 1)
+	exit = func(code int) { exitCodes <- code }
+	defer func() { exit = os.Exit }()
+
+	session := NewSession(strings.NewReader(""), io.Discard)
+	hookRan := false
+	session.OnShutdown(func() { hookRan = true })
+	stop := session.TrapSignals()
+	defer stop()
+
+	// the first signal waits to be handled by a read, which doesn't happen
+	signalSelf(t, syscall.SIGTERM)
+	for deadline := time.Now().Add(time.Second); len(session.trappedSignals()) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("signal wasn't trapped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	signalSelf(t, os.Interrupt)
+
+	select {
+	case code := <-exitCodes:
+		if code != interruptedExitCode || !hookRan {
+			t.Errorf("got exit code %d with hooks run %v, expected %d after the hooks", code, hookRan, interruptedExitCode)
+		}
+	case <-time.After(time.Second):
+		t.Error("program didn't exit after an unhandled signal")
+	}
+}
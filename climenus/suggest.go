@@ -0,0 +1,189 @@
+package climenus
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// max number of suggestions given for an unknown command
+const maxSuggestions = 3
+
+// min length of a description word compared against unknown input,
+// so short words like "a" don't match everything
+const minSuggestionWordLen = 3
+
+// error returned by the menu's command validator when input doesn't match a command,
+// with the commands closest to the input as suggestions
+type UnknownCommandError struct {
+	Input       string   // the command that was entered
+	Suggestions []string // inputs for the closest commands, closest first
+	labels      []string // how each suggestion is shown in the error message
+}
+
+// returns the error message with any suggestions, e.g.
+// not a valid command, did you mean "edit"?
+func (e *UnknownCommandError) Error() string {
+	switch len(e.labels) {
+	case 0:
+		return "not a valid command"
+	case 1:
+		return fmt.Sprintf("not a valid command, did you mean %s?", e.labels[0])
+	}
+	return fmt.Sprintf("not a valid command, did you mean one of %s?", strings.Join(e.labels, ", "))
+}
+
+// struct for a command that is close to some unknown input
+type suggestion struct {
+	input    string // input that issues the command
+	label    string // how the suggestion is shown
+	distance int    // edit distance between the unknown input and the command
+}
+
+// returns an error for unknown input, suggesting the commands and built-in commands
+// whose names, aliases or descriptions are closest to it by edit distance
+func (menu *Menu) unknownCommandError(input string) *UnknownCommandError {
+	err := &UnknownCommandError{Input: input}
+	if input == "" {
+		return err
+	}
+
+	word := strings.ToLower(input)
+	// allow about one typo for every three characters entered
+	maxDistance := max(1, utf8.RuneCountInString(word)/3)
+
+	suggestions := make([]suggestion, 0)
+	for _, command := range menu.Commands {
+		distance := commandDistance(word, command)
+		if distance > maxDistance {
+			continue
+		}
+		if command.Name != "" {
+			suggestions = append(suggestions, suggestion{command.Name, strconv.Quote(command.Name), distance})
+		} else {
+			optionNumber := strconv.Itoa(command.OptionNumber)
+			suggestions = append(suggestions, suggestion{optionNumber, fmt.Sprintf("%s (%s)", optionNumber, command.Description), distance})
+		}
+	}
+	for _, builtin := range menu.builtins() {
+		// commands registered on the menu take precedence over built-in commands
+		if _, isCommand := menu.CommandsMap[builtin.name]; isCommand || builtin.prefix {
+			continue
+		}
+		if distance := editDistance(word, builtin.name); distance <= maxDistance {
+			suggestions = append(suggestions, suggestion{builtin.name, strconv.Quote(builtin.name), distance})
+		}
+	}
+
+	// closest first, keeping menu order for equally close commands
+	slices.SortStableFunc(suggestions, func(a, b suggestion) int {
+		return a.distance - b.distance
+	})
+	for _, s := range suggestions[:min(len(suggestions), maxSuggestions)] {
+		err.Suggestions = append(err.Suggestions, s.input)
+		err.labels = append(err.labels, s.label)
+	}
+
+	return err
+}
+
+// returns the smallest edit distance between word and the command's name, its aliases,
+// its description, and the words of its description
+func commandDistance(word string, command *Command) int {
+	candidates := command.names()
+	if command.Description != "" {
+		description := strings.ToLower(command.Description)
+		candidates = append(candidates, description)
+		for _, descriptionWord := range strings.Fields(description) {
+			if utf8.RuneCountInString(descriptionWord) >= minSuggestionWordLen {
+				candidates = append(candidates, descriptionWord)
+			}
+		}
+	}
+
+	distance := -1
+	for _, candidate := range candidates {
+		d := editDistance(word, strings.ToLower(candidate))
+		if distance < 0 || d < distance {
+			distance = d
+		}
+	}
+	if distance < 0 {
+		return utf8.RuneCountInString(word)
+	}
+	return distance
+}
+
+// returns the number of single rune insertions, deletions, substitutions, and swaps
+// of adjacent runes needed to change a into b (optimal string alignment distance)
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// rows i-2, i-1 and i of the distance matrix
+	prevPrev := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prevPrev[j-2]+1)
+			}
+		}
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+
+	return prev[len(rb)]
+}
+
+// reads command input for the menu. If AcceptSuggestions is set and unknown input has
+// a single suggestion, pressing Enter on the next prompt issues the suggested command
+// with the args that were entered. Returns an error if input ends or is stopped first.
+func (menu *Menu) readCommandInput() (string, error) {
+	// input for the suggested command, set after unknown input with one suggestion
+	suggested := ""
+	validator := func(input string) (bool, error) {
+		if input == "" && suggested != "" {
+			isValid, err := menu.commandValidator(suggested)
+			if !isValid {
+				suggested = ""
+			}
+			return isValid, err
+		}
+		suggested = ""
+
+		isValid, err := menu.commandValidator(input)
+		var unknownErr *UnknownCommandError
+		if menu.AcceptSuggestions && errors.As(err, &unknownErr) && len(unknownErr.Suggestions) == 1 {
+			suggested = unknownErr.Suggestions[0]
+			// keep the args entered after the unknown command
+			if _, args, hasArgs := strings.Cut(strings.TrimSpace(input), " "); hasArgs {
+				suggested += " " + args
+			}
+			return false, fmt.Errorf("%w (press Enter to use it)", err)
+		}
+		return isValid, err
+	}
+
+	session := menu.session()
+	// a script may end when a menu is waiting for a command, but not in the middle of one
+	session.awaitingCommand = true
+	defer func() { session.awaitingCommand = false }()
+	input, err := session.userInputContext(session.loopCtx, "", validator, menu.Completions, menu.theme())
+	if input == "" && suggested != "" {
+		return suggested, err
+	}
+	return input, err
+}
@@ -0,0 +1,97 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	testCases := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "", b: "", expected: 0},
+		{a: "", b: "add", expected: 3},
+		{a: "edit", b: "edit", expected: 0},
+		{a: "edt", b: "edit", expected: 1},
+		{a: "hlep", b: "help", expected: 1},
+		{a: "kitten", b: "sitting", expected: 3},
+		{a: "crème", b: "creme", expected: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			distance := editDistance(tc.a, tc.b)
+			if distance != tc.expected {
+				t.Errorf("got %v, expected %v", distance, tc.expected)
+			}
+		})
+	}
+}
+
+func TestUnknownCommandSuggestions(t *testing.T) {
+	menu := &Menu{}
+	menu.AddCommand(&Command{Name: "edit", Description: "Edit a Recipe"})
+	menu.AddCommand(&Command{Name: "exit", Aliases: []string{"quit"}, Description: "Exit Program"})
+	menu.AddCommand(&Command{Description: "Pasta Carbonara"})
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "edt", expected: `not a valid command, did you mean "edit"?`},
+		{input: "exti", expected: `not a valid command, did you mean "exit"?`},
+		{input: "qiut", expected: `not a valid command, did you mean "exit"?`},
+		{input: "recipe", expected: `not a valid command, did you mean "edit"?`},
+		{input: "carbonarra", expected: `not a valid command, did you mean 3 (Pasta Carbonara)?`},
+		{input: "hepl", expected: `not a valid command, did you mean "help"?`},
+		{input: "eit", expected: `not a valid command, did you mean one of "edit", "exit"?`},
+		{input: "nothing", expected: "not a valid command"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			isValid, err := menu.commandValidator(tc.input)
+			var unknownErr *UnknownCommandError
+			if isValid || !errors.As(err, &unknownErr) {
+				t.Fatalf("got %v %v, expected an UnknownCommandError", isValid, err)
+			}
+			if err.Error() != tc.expected {
+				t.Errorf("got %q, expected %q", err.Error(), tc.expected)
+			}
+		})
+	}
+}
+
+func TestMenuLoopAcceptSuggestion(t *testing.T) {
+	var out bytes.Buffer
+	menu := &Menu{AcceptSuggestions: true}
+	// Enter accepts "scale 2", Enter after input with several suggestions does nothing
+	menu.Session = NewSession(strings.NewReader("scael 2\n\nsae\n\nback\n"), &out)
+
+	var factor float64
+	menu.AddCommand(&Command{Name: "scale", ArgSpecs: scaleArgSpecs, Run: func(args *Args, m *Menu) error {
+		factor = args.Float("factor")
+		return nil
+	}})
+	menu.AddCommand(&Command{Name: "save"})
+	menu.AddCommand(&Command{Name: "sage"})
+
+	err := menu.MenuLoop()
+	if err != nil {
+		t.Errorf("got error %v", err.Error())
+	}
+
+	if factor != 2 {
+		t.Errorf("got %v, expected %v", factor, 2)
+	}
+	if !strings.Contains(out.String(), `not a valid command, did you mean "scale"? (press Enter to use it)`) {
+		t.Errorf("output %q does not contain suggestion", out.String())
+	}
+	if !strings.Contains(out.String(), `did you mean one of "save", "sage"?`+"\n") {
+		t.Errorf("output %q does not contain suggestions", out.String())
+	}
+}
@@ -0,0 +1,9 @@
+//go:build darwin || freebsd || netbsd || openbsd
+
+package climenus
+
+import "syscall"
+
+// ioctl requests for reading and writing terminal attributes
+const ioctlGetTermios = syscall.TIOCGETA
+const ioctlSetTermios = syscall.TIOCSETA
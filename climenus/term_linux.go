@@ -0,0 +1,7 @@
+package climenus
+
+import "syscall"
+
+// ioctl requests for reading and writing terminal attributes
+const ioctlGetTermios = syscall.TCGETS
+const ioctlSetTermios = syscall.TCSETS
@@ -0,0 +1,26 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package climenus
+
+import (
+	"errors"
+	"os"
+)
+
+// terminals aren't detected on this platform, so input is always read as plain lines
+func isTerminal(f *os.File) bool {
+	return false
+}
+
+// raw mode isn't supported on this platform
+func makeRaw(f *os.File) (func(), error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+// terminal size isn't detected on this platform
+func terminalWidth(f *os.File) (int, error) {
+	return 0, errors.New("terminal width is not supported on this platform")
+}
+
+// raw mode isn't supported on this platform, so Ctrl+C is never read as a key
+func raiseInterrupt() {}
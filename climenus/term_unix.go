@@ -0,0 +1,84 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package climenus
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// reads the terminal attributes of the file descriptor
+func getTermios(fd uintptr) (*syscall.Termios, error) {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(&termios)))
+	if errno != 0 {
+		return nil, errno
+	}
+	return &termios, nil
+}
+
+// sets the terminal attributes of the file descriptor
+func setTermios(fd uintptr, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// returns true if the file is a terminal
+func isTerminal(f *os.File) bool {
+	_, err := getTermios(f.Fd())
+	return err == nil
+}
+
+// puts the terminal into raw mode, so input is read a key at a time without being echoed.
+// Keys such as Ctrl+C are read like other keys rather than sending signals, so the
+// terminal is never left in raw mode by a signal. Returns a function restoring the previous mode.
+func makeRaw(f *os.File) (func(), error) {
+	fd := f.Fd()
+	previous, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *previous
+	raw.Iflag &^= syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ICANON | syscall.ECHO | syscall.IEXTEN | syscall.ISIG
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	err = setTermios(fd, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() { setTermios(fd, previous) }, nil
+}
+
+// sends SIGINT to the program, for Ctrl+C read as a key in raw mode
+func raiseInterrupt() {
+	syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+}
+
+// window size of a terminal, as read with TIOCGWINSZ
+type winsize struct {
+	rows    uint16
+	cols    uint16
+	xpixels uint16
+	ypixels uint16
+}
+
+// returns the width in columns of the terminal the file is connected to
+func terminalWidth(f *os.File) (int, error) {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0, errno
+	}
+	if ws.cols == 0 {
+		return 0, errors.New("terminal width is unknown")
+	}
+	return int(ws.cols), nil
+}
@@ -0,0 +1,137 @@
+package climenus
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// environment variable that turns off colors when set to anything, see https://no-color.org
+const noColorEnv = "NO_COLOR"
+
+// ANSI escape sequence that resets all styles
+const resetStyle = "\x1b[0m"
+
+// ANSI SGR parameters for styling text, e.g. "1" for bold or "1;36" for bold cyan.
+// The empty Style leaves text as it is.
+type Style string
+
+// returns the text with the style applied, the text as it is for an empty style
+func (s Style) Render(text string) string {
+	if s == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + string(s) + "m" + text + resetStyle
+}
+
+// returns a style combining this style with other, with other taking precedence
+// where they set the same attribute
+func (s Style) Add(other Style) Style {
+	if s == "" || other == "" {
+		return s + other
+	}
+	return s + ";" + other
+}
+
+// struct holding the styles used when showing a menu and taking input. Themes are set
+// on a Menu and used by its submenus, and only applied when the session's output
+// supports colors (see Session.ColorEnabled).
+type Theme struct {
+	Header       Style // column labels
+	Separator    Style // line between the column labels and the commands
+	Row          Style // rows of commands
+	AltRow       Style // rows of every other command, for striping (Row is used if empty)
+	OptionNumber Style // option numbers, added to the row style
+	Breadcrumbs  Style // path to the menu shown above submenus
+	Info         Style // filter and page lines shown with the commands
+	Error        Style // errors, e.g. for invalid input
+	Prompt       Style // prompts for input
+}
+
+// theme without any styles, used to turn off a theme set on a parent menu
+var PlainTheme = &Theme{}
+
+// theme with bold headers and option numbers, and colored errors and breadcrumbs
+var DefaultTheme = &Theme{
+	Header:       "1",
+	Separator:    "2",
+	OptionNumber: "1;36",
+	Breadcrumbs:  "34",
+	Info:         "2",
+	Error:        "31",
+	Prompt:       "1",
+}
+
+// DefaultTheme with every other row shaded, for wide menus with many rows
+var StripedTheme = &Theme{
+	Header:       "1",
+	Separator:    "2",
+	AltRow:       "48;5;236",
+	OptionNumber: "1;36",
+	Breadcrumbs:  "34",
+	Info:         "2",
+	Error:        "31",
+	Prompt:       "1",
+}
+
+// theme with bright, bold colors for readability
+var HighContrastTheme = &Theme{
+	Header:       "1;7",
+	Separator:    "1;97",
+	Row:          "97",
+	OptionNumber: "1;93",
+	Breadcrumbs:  "1;96",
+	Info:         "1;97",
+	Error:        "1;91",
+	Prompt:       "1;93",
+}
+
+// returns the style for the rows of the command at index i of the commands shown
+func (t *Theme) rowStyle(i int) Style {
+	if i%2 == 1 && t.AltRow != "" {
+		return t.AltRow
+	}
+	return t.Row
+}
+
+// returns true if styles are applied to this session's output: always if Color is
+// ColorAlways, never if it is ColorNever, and otherwise only if Out is a terminal
+// and the NO_COLOR environment variable isn't set
+func (s *Session) ColorEnabled() bool {
+	switch s.Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv(noColorEnv) != "" {
+		return false
+	}
+	file, ok := s.outFile()
+	return ok && isTerminal(file)
+}
+
+// returns the theme for this menu, falling back to the theme of the menu it was opened
+// from as a SubMenu. PlainTheme is returned if there is none or colors aren't enabled
+func (menu *Menu) theme() *Theme {
+	if !menu.session().ColorEnabled() {
+		return PlainTheme
+	}
+	for m := menu; m != nil; m = m.parent {
+		if m.Theme != nil {
+			return m.Theme
+		}
+	}
+	return PlainTheme
+}
+
+// prints an error to the menu's output in the theme's error style
+func (menu *Menu) printError(err error) {
+	fmt.Fprintln(menu.Out(), menu.theme().Error.Render(err.Error()))
+}
+
+// returns the line of dashes between the column labels and the commands
+func separatorLine(width int) string {
+	return strings.Repeat("-", width)
+}
@@ -0,0 +1,127 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStyle(t *testing.T) {
+	if rendered := Style("1;36").Render("add"); rendered != "\x1b[1;36madd\x1b[0m" {
+		t.Errorf("got %q, expected %q", rendered, "\x1b[1;36madd\x1b[0m")
+	}
+	if rendered := Style("").Render("add"); rendered != "add" {
+		t.Errorf("got %q, expected %q", rendered, "add")
+	}
+	if rendered := Style("1").Render(""); rendered != "" {
+		t.Errorf("got %q, expected %q", rendered, "")
+	}
+
+	testCases := []struct {
+		a        Style
+		b        Style
+		expected Style
+	}{
+		{a: "", b: "", expected: ""},
+		{a: "48;5;236", b: "", expected: "48;5;236"},
+		{a: "", b: "1;36", expected: "1;36"},
+		{a: "48;5;236", b: "1;36", expected: "48;5;236;1;36"},
+	}
+	for _, tc := range testCases {
+		if added := tc.a.Add(tc.b); added != tc.expected {
+			t.Errorf("got %q, expected %q", added, tc.expected)
+		}
+	}
+}
+
+func TestSessionColorEnabled(t *testing.T) {
+	testCases := []struct {
+		name     string
+		color    ColorMode
+		out      io.Writer
+		noColor  string
+		expected bool
+	}{
+		{name: "testAutoNotTerminal", color: ColorAuto, out: io.Discard, expected: false},
+		{name: "testAlways", color: ColorAlways, out: io.Discard, expected: true},
+		{name: "testAlwaysIgnoresNoColor", color: ColorAlways, out: io.Discard, noColor: "1", expected: true},
+		{name: "testNever", color: ColorNever, out: io.Discard, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(noColorEnv, tc.noColor)
+			session := NewSession(nil, tc.out)
+			session.Color = tc.color
+			if enabled := session.ColorEnabled(); enabled != tc.expected {
+				t.Errorf("got %v, expected %v", enabled, tc.expected)
+			}
+		})
+	}
+}
+
+func TestShowMenuWithTheme(t *testing.T) {
+	var out bytes.Buffer
+	menu := newTestMenu("", &out, "add", "edit")
+	menu.Theme = StripedTheme
+	menu.Session.Color = ColorAlways
+	menu.ShowMenu()
+
+	expected := "\n\n\n" +
+		"\x1b[1m # Name  \x1b[0m\n" +
+		"\x1b[2m" + strings.Repeat("-", 12) + "\x1b[0m\n" +
+		"\x1b[1;36m 1 \x1b[0madd   \n" +
+		"\x1b[48;5;236;1;36m 2 \x1b[0m\x1b[48;5;236medit  \x1b[0m\n"
+	if out.String() != expected {
+		t.Errorf("got %q, expected %q", out.String(), expected)
+	}
+}
+
+func TestThemeDisabled(t *testing.T) {
+	var plain bytes.Buffer
+	plainMenu := newTestMenu("", &plain, "add", "edit")
+	plainMenu.Session.Color = ColorAlways
+	plainMenu.ShowMenu()
+
+	testCases := []struct {
+		name  string
+		theme *Theme
+		color ColorMode
+	}{
+		{name: "testNoColor", theme: DefaultTheme, color: ColorNever},
+		{name: "testNotTerminal", theme: HighContrastTheme, color: ColorAuto},
+		{name: "testPlainTheme", theme: PlainTheme, color: ColorAlways},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			menu := newTestMenu("", &out, "add", "edit")
+			menu.Theme = tc.theme
+			menu.Session.Color = tc.color
+			menu.ShowMenu()
+			if out.String() != plain.String() {
+				t.Errorf("got %q, expected %q", out.String(), plain.String())
+			}
+		})
+	}
+}
+
+func TestThemeInheritedBySubMenu(t *testing.T) {
+	var out bytes.Buffer
+	parent := newTestMenu("", &out, "add", "edit")
+	parent.Theme = DefaultTheme
+	parent.Session.Color = ColorAlways
+	subMenu := &Menu{parent: parent}
+
+	if subMenu.theme() != DefaultTheme {
+		t.Errorf("submenu doesn't use the theme of its parent")
+	}
+
+	subMenu.printError(errors.New("not a valid command"))
+	if out.String() != "\x1b[31mnot a valid command\x1b[0m\n" {
+		t.Errorf("got %q, expected a styled error", out.String())
+	}
+}
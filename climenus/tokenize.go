@@ -0,0 +1,92 @@
+package climenus
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Splits input into words the way a shell would: words are separated by any amount
+// of whitespace, single quotes keep everything inside them as is, double quotes keep
+// whitespace but allow \" and \\ escapes, and a backslash outside quotes escapes the
+// next character. Quoted empty strings are kept as empty words.
+// Returns an error for an unterminated quote or a trailing backslash.
+func Tokenize(input string) ([]string, error) {
+	words := make([]string, 0)
+	var sb strings.Builder
+	// whether a word is being built, needed since quoted words can be empty
+	inWord := false
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, sb.String())
+				sb.Reset()
+				inWord = false
+			}
+		case r == '\\':
+			if i+1 >= len(runes) {
+				return nil, errors.New("input ends with an unescaped backslash")
+			}
+			i++
+			sb.WriteRune(runes[i])
+			inWord = true
+		case r == '\'':
+			end := indexRune(runes, '\'', i+1)
+			if end < 0 {
+				return nil, errors.New("unterminated single quote in input")
+			}
+			sb.WriteString(string(runes[i+1 : end]))
+			i = end
+			inWord = true
+		case r == '"':
+			end, err := readDoubleQuoted(runes, i+1, &sb)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			inWord = true
+		default:
+			sb.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, sb.String())
+	}
+
+	return words, nil
+}
+
+// writes the contents of a double quoted string starting at runes[start] to sb,
+// handling \" and \\ escapes. Returns the index of the closing quote.
+func readDoubleQuoted(runes []rune, start int, sb *strings.Builder) (int, error) {
+	for i := start; i < len(runes); i++ {
+		switch runes[i] {
+		case '"':
+			return i, nil
+		case '\\':
+			// only quotes and backslashes are escaped inside double quotes
+			if i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+				i++
+			}
+		}
+		sb.WriteRune(runes[i])
+	}
+
+	return -1, errors.New("unterminated double quote in input")
+}
+
+// returns the index of the first r in runes at or after start, or -1 if there is none
+func indexRune(runes []rune, r rune, start int) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
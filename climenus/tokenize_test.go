@@ -0,0 +1,104 @@
+package climenus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expected    []string
+		expectedErr string
+	}{
+		{name: "testPlainWords", input: "scale 1.5", expected: []string{"scale", "1.5"}},
+		{name: "testCollapsedWhitespace", input: "  scale \t 1.5  ", expected: []string{"scale", "1.5"}},
+		{name: "testEmpty", input: "   ", expected: []string{}},
+		{name: "testDoubleQuotes", input: `rename "Grandma's  Pasta"`, expected: []string{"rename", "Grandma's  Pasta"}},
+		{name: "testSingleQuotes", input: `say 'a "quoted" \n word'`, expected: []string{"say", `a "quoted" \n word`}},
+		{name: "testEscapedQuoteInDoubleQuotes", input: `say "a \"b\" \\ \c"`, expected: []string{"say", `a "b" \ \c`}},
+		{name: "testBackslashEscapes", input: `say a\ b \'c`, expected: []string{"say", "a b", "'c"}},
+		{name: "testEmptyQuotedWord", input: `set name ""`, expected: []string{"set", "name", ""}},
+		{name: "testAdjacentQuotes", input: `a"b c"'d e'`, expected: []string{"ab cd e"}},
+		{name: "testUnicode", input: `añadir "crème brûlée"`, expected: []string{"añadir", "crème brûlée"}},
+		{name: "testUnterminatedDouble", input: `say "oops`, expectedErr: "unterminated double quote in input"},
+		{name: "testUnterminatedSingle", input: `say 'oops`, expectedErr: "unterminated single quote in input"},
+		{name: "testTrailingBackslash", input: `say oops\`, expectedErr: "input ends with an unescaped backslash"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			words, err := Tokenize(tc.input)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("got error %v", err.Error())
+			}
+			if len(words) != len(tc.expected) {
+				t.Fatalf("got %q, expected %q", words, tc.expected)
+			}
+			for i := range words {
+				if words[i] != tc.expected[i] {
+					t.Errorf("got %q, expected %q", words[i], tc.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMenuLoopQuotedArgs(t *testing.T) {
+	var out bytes.Buffer
+	var menu Menu
+	menu.Session = NewSession(strings.NewReader("rename \"unterminated\nrename  \"My  Pasta\"\nback\n"), &out)
+
+	name := ""
+	menu.AddCommand(&Command{
+		Name:     "rename",
+		ArgSpecs: []ArgSpec{{Name: "name", Required: true}},
+		Run: func(args *Args, m *Menu) error {
+			name = args.String("name")
+			return nil
+		},
+	})
+
+	err := menu.MenuLoop()
+	if err != nil {
+		t.Errorf("got error %v", err.Error())
+	}
+	if name != "My  Pasta" {
+		t.Errorf("got %q, expected %q", name, "My  Pasta")
+	}
+	if !strings.Contains(out.String(), "unterminated double quote in input") {
+		t.Errorf("output %q does not contain the tokenize error", out.String())
+	}
+}
+
+func TestMenuLoopEmptyInput(t *testing.T) {
+	var out bytes.Buffer
+	var menu Menu
+	menu.Session = NewSession(strings.NewReader("\n   \n2\n"), &out)
+
+	var selected []int
+	selectRecipe := func(args *Args, m *Menu) error {
+		selected = append(selected, args.Command.OptionNumber)
+		return nil
+	}
+	menu.AddCommand(&Command{Description: "Pasta", Run: selectRecipe})
+	menu.AddCommand(&Command{Description: "Soup", Run: selectRecipe})
+
+	// empty input only prompts again, commands without a name are selected by number
+	menu.MenuLoop()
+	if len(selected) != 1 || selected[0] != 2 {
+		t.Errorf("got commands %v selected, expected [2]", selected)
+	}
+	if _, ok := menu.CommandsMap[""]; ok {
+		t.Errorf("commands without a name shouldn't be registered by name")
+	}
+}
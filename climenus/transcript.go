@@ -0,0 +1,233 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// first line of a transcript
+const transcriptHeader = "# climenus transcript"
+
+// prefixes of the lines of a transcript
+const transcriptOutput = "|"  // a line of output
+const transcriptPartial = ":" // output without a newline, e.g. before input on the same line
+const transcriptInput = ">"   // a line of input
+const transcriptComment = "#" // the header, settings and comments
+
+// settings in a transcript's header, needed to show its menus the same way again
+const transcriptWidth = "width" // width the menus were laid out for
+const transcriptColor = "color" // whether themes were applied, "on" or "off"
+
+// records the output and input of a session as a transcript, which can be replayed
+// with ReplayTranscript. Output lines are written prefixed with "| " and input lines
+// with "> ", after a header with the settings the menus were shown with.
+// Created by Session.Record, which sets it as the session's Out until it is closed.
+type Recorder struct {
+	session    *Session
+	out        io.Writer // output of the session before recording, which output is passed on to
+	transcript io.Writer // writer the transcript is written to
+	partial    []byte    // output after the last newline, not recorded yet
+	err        error     // first error writing the transcript
+}
+
+// starts recording the session's output and input as a transcript written to w.
+// The returned Recorder must be closed to finish the transcript
+func (s *Session) Record(w io.Writer) *Recorder {
+	r := &Recorder{session: s, out: s.Out, transcript: w}
+	color := "off"
+	if s.ColorEnabled() {
+		color = "on"
+	}
+	r.writeLine(transcriptHeader)
+	r.writeLine(fmt.Sprintf("%s %s %d", transcriptComment, transcriptWidth, s.TerminalWidth()))
+	r.writeLine(fmt.Sprintf("%s %s %s", transcriptComment, transcriptColor, color))
+
+	s.Out = r
+	s.recorder = r
+	return r
+}
+
+// writes output to the session's output, recording it in the transcript
+func (r *Recorder) Write(p []byte) (int, error) {
+	n, err := r.out.Write(p)
+	r.partial = append(r.partial, p[:n]...)
+	for {
+		i := bytes.IndexByte(r.partial, '\n')
+		if i < 0 {
+			break
+		}
+		r.writeEntry(transcriptOutput, string(r.partial[:i]))
+		r.partial = r.partial[i+1:]
+	}
+	return n, err
+}
+
+// records a line of input read by the session
+func (r *Recorder) recordInput(line string) {
+	r.flush()
+	r.writeEntry(transcriptInput, line)
+}
+
+// stops recording, restoring the session's output, and returns the first error
+// writing the transcript
+func (r *Recorder) Close() error {
+	r.flush()
+	if r.session.recorder == r {
+		r.session.Out = r.out
+		r.session.recorder = nil
+	}
+	return r.err
+}
+
+// records output written since the last newline
+func (r *Recorder) flush() {
+	if len(r.partial) > 0 {
+		r.writeEntry(transcriptPartial, string(r.partial))
+		r.partial = r.partial[:0]
+	}
+}
+
+// writes a line of the transcript with the given prefix, separated from the text by
+// a space unless the text is empty
+func (r *Recorder) writeEntry(prefix string, text string) {
+	if text == "" {
+		r.writeLine(prefix)
+		return
+	}
+	r.writeLine(prefix + " " + text)
+}
+
+// writes a line to the transcript, keeping the first error
+func (r *Recorder) writeLine(line string) {
+	if r.err != nil {
+		return
+	}
+	_, r.err = io.WriteString(r.transcript, line+"\n")
+}
+
+// returns the file the session writes to, if its output (before any recording) is a file
+func (s *Session) outFile() (*os.File, bool) {
+	out := s.Out
+	if r, ok := out.(*Recorder); ok {
+		out = r.out
+	}
+	file, ok := out.(*os.File)
+	return file, ok
+}
+
+// error for a replayed transcript that doesn't match the recorded transcript
+type TranscriptMismatchError struct {
+	Line     int    // line of the transcript with the first difference
+	Expected string // recorded line, empty if the replay went on past the end of the transcript
+	Got      string // replayed line, empty if the replay ended before the end of the transcript
+}
+
+func (e *TranscriptMismatchError) Error() string {
+	return fmt.Sprintf("transcript line %d: expected %q, got %q", e.Line, e.Expected, e.Got)
+}
+
+// settings and inputs read from a transcript
+type transcript struct {
+	width  int
+	color  bool
+	inputs []string
+}
+
+// reads the settings and inputs from a transcript, returns an error for lines
+// that aren't part of a transcript
+func parseTranscript(text string) (*transcript, error) {
+	t := &transcript{}
+	for i, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, transcriptInput):
+			t.inputs = append(t.inputs, entryText(line))
+		case strings.HasPrefix(line, transcriptComment):
+			setting, value, _ := strings.Cut(entryText(line), " ")
+			switch setting {
+			case transcriptWidth:
+				width, err := strconv.Atoi(value)
+				if err != nil || width <= 0 {
+					return nil, fmt.Errorf("transcript line %d: invalid width %q", i+1, value)
+				}
+				t.width = width
+			case transcriptColor:
+				t.color = value == "on"
+			}
+		case strings.HasPrefix(line, transcriptOutput), strings.HasPrefix(line, transcriptPartial):
+		default:
+			return nil, fmt.Errorf("transcript line %d: %q isn't output, input or a comment", i+1, line)
+		}
+	}
+	return t, nil
+}
+
+// returns the text of a transcript line, without its prefix and the space after it
+func entryText(line string) string {
+	return strings.TrimPrefix(line[1:], " ")
+}
+
+// runs the menu's MenuLoop with the inputs of a transcript recorded with Session.Record,
+// laying out menus for the same width and with the same colors, and compares the
+// transcript of the replay to the recorded one. Returns the replayed transcript
+// (e.g. to update a golden file) and a *TranscriptMismatchError for the first line that
+// differs, or another error if the replay couldn't be run. The replay ends when the
+// inputs run out, like a script (see RunScript), but invalid inputs are prompted
+// for again as they were when recorded.
+func ReplayTranscript(menu *Menu, recorded io.Reader) (string, error) {
+	data, err := io.ReadAll(recorded)
+	if err != nil {
+		return "", err
+	}
+	t, err := parseTranscript(string(data))
+	if err != nil {
+		return "", err
+	}
+
+	inputs := strings.Join(t.inputs, "\n")
+	session := NewSession(strings.NewReader(inputs), io.Discard)
+	session.Width = t.width
+	session.Color = ColorNever
+	if t.color {
+		session.Color = ColorAlways
+	}
+	session.replay = true
+
+	previous := menu.Session
+	menu.Session = session
+	defer func() { menu.Session = previous }()
+
+	var replayed strings.Builder
+	recorder := session.Record(&replayed)
+	err = menu.MenuLoop()
+	recorder.Close()
+	if err != nil && !errors.Is(err, ErrExitProgram) && !errors.Is(err, ErrScriptEnded) {
+		return replayed.String(), err
+	}
+
+	return replayed.String(), compareTranscripts(string(data), replayed.String())
+}
+
+// returns a *TranscriptMismatchError for the first line that differs between
+// the transcripts, nil if they're the same
+func compareTranscripts(expected string, got string) error {
+	expectedLines := strings.Split(strings.TrimSuffix(expected, "\n"), "\n")
+	gotLines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	for i := range max(len(expectedLines), len(gotLines)) {
+		var e, g string
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if e != g || i >= len(expectedLines) || i >= len(gotLines) {
+			return &TranscriptMismatchError{Line: i + 1, Expected: e, Got: g}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package climenus
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden transcripts in testdata")
+
+// golden transcript of scriptTestMenu, with an invalid input prompted for again
+var goldenTranscript = filepath.Join("testdata", "add.transcript")
+
+// records a session of scriptTestMenu with the given input
+func recordTestSession(t *testing.T, input string) string {
+	var out, transcript bytes.Buffer
+	var numbers []string
+	menu := scriptTestMenu(&out, &numbers)
+	menu.Session = NewSession(strings.NewReader(input), &out)
+	menu.Session.Width = 40
+
+	recorder := menu.Session.Record(&transcript)
+	err := menu.MenuLoop()
+	if !errors.Is(err, ErrExitProgram) {
+		t.Fatalf("got error %v, expected the program to exit", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if menu.Session.Out != &out {
+		t.Errorf("session output wasn't restored")
+	}
+	return transcript.String()
+}
+
+func TestRecordTranscript(t *testing.T) {
+	transcript := recordTestSession(t, "add\nten\n10\nmore\nadd\n  3\nback\nexit\n")
+	if *update {
+		if err := os.WriteFile(goldenTranscript, []byte(transcript), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	golden, err := os.ReadFile(goldenTranscript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := compareTranscripts(string(golden), transcript); err != nil {
+		t.Errorf("transcript doesn't match %s: %v", goldenTranscript, err)
+	}
+}
+
+func TestReplayTranscript(t *testing.T) {
+	golden, err := os.ReadFile(goldenTranscript)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	var numbers []string
+	menu := scriptTestMenu(&out, &numbers)
+	session := menu.Session
+	replayed, err := ReplayTranscript(menu, bytes.NewReader(golden))
+	if err != nil {
+		t.Errorf("got error %v, expected the replay to match", err)
+	}
+	if replayed != string(golden) {
+		t.Errorf("got %q, expected %q", replayed, string(golden))
+	}
+	if strings.Join(numbers, ",") != "10,3" {
+		t.Errorf("got numbers %v, expected [10 3]", numbers)
+	}
+	if menu.Session != session {
+		t.Errorf("menu session wasn't restored")
+	}
+
+	// a change to the menu is found by the replay
+	menu = scriptTestMenu(&out, &numbers)
+	menu.Commands[1].Name = "fails"
+	menu.CommandsMap["fails"] = menu.Commands[1]
+	_, err = ReplayTranscript(menu, bytes.NewReader(golden))
+	var mismatch *TranscriptMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("got error %v, expected a mismatch", err)
+	}
+	if mismatch.Line != 10 || mismatch.Expected != "|  2 fail  " || mismatch.Got != "|  2 fails " {
+		t.Errorf("got %v, expected a mismatch on line 10", mismatch)
+	}
+}
+
+func TestReplayEndsEarly(t *testing.T) {
+	// the recorded session exits, the replay stops when the inputs run out
+	transcript := recordTestSession(t, "add\n5\nexit\n")
+	cut := strings.Index(transcript, "> exit\n")
+	var out bytes.Buffer
+	var numbers []string
+	_, err := ReplayTranscript(scriptTestMenu(&out, &numbers), strings.NewReader(transcript[:cut]))
+	if err != nil {
+		t.Errorf("got error %v, expected the replay to match", err)
+	}
+
+	_, err = ReplayTranscript(scriptTestMenu(&out, &numbers), strings.NewReader(transcript+"| extra\n"))
+	var mismatch *TranscriptMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Expected != "| extra" || mismatch.Got != "" {
+		t.Errorf("got error %v, expected the missing line to be found", err)
+	}
+}
+
+func TestRecordPartialOutput(t *testing.T) {
+	var out, transcript bytes.Buffer
+	session := NewSession(strings.NewReader("yes\n\n"), &out)
+	session.Width = 20
+	recorder := session.Record(&transcript)
+	session.Out.Write([]byte("first\n\nsecond? "))
+	session.readLine(nil)
+	session.readLine(nil)
+	session.Out.Write([]byte("done"))
+	recorder.Close()
+
+	expected := transcriptHeader + "\n# width 20\n# color off\n" +
+		"| first\n|\n: second? \n> yes\n>\n: done\n"
+	if transcript.String() != expected {
+		t.Errorf("got %q, expected %q", transcript.String(), expected)
+	}
+	if out.String() != "first\n\nsecond? done" {
+		t.Errorf("got output %q, expected it to be passed on", out.String())
+	}
+}
+
+func TestParseTranscript(t *testing.T) {
+	testCases := []struct {
+		name        string
+		transcript  string
+		expectedErr string
+	}{
+		{name: "testValid", transcript: "# width 30\n# color on\n# a comment\n| out\n: partial\n> in\n>\n"},
+		{name: "testInvalidWidth", transcript: "# width wide\n", expectedErr: `transcript line 1: invalid width "wide"`},
+		{name: "testUnknownLine", transcript: "| out\nin\n", expectedErr: `transcript line 2: "in" isn't output, input or a comment`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := parseTranscript(tc.transcript)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Errorf("got error %v, expected %v", err, tc.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+			if parsed.width != 30 || !parsed.color || strings.Join(parsed.inputs, ",") != "in," {
+				t.Errorf("got %+v, expected width 30, color and inputs [in ]", parsed)
+			}
+		})
+	}
+}
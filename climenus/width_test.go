@@ -0,0 +1,86 @@
+package climenus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	testCases := []struct {
+		s        string
+		expected int
+	}{
+		{s: "pasta", expected: 5},
+		{s: "crème brûlée", expected: 12},
+		{s: "crème", expected: 5},
+		{s: "寿司", expected: 4},
+		{s: "ラーメン", expected: 8},
+		{s: "🍝 pasta", expected: 8},
+		{s: "김치", expected: 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.s, func(t *testing.T) {
+			width := displayWidth(tc.s)
+			if width != tc.expected {
+				t.Errorf("got %v, expected %v", width, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	testCases := []struct {
+		name     string
+		s        string
+		width    int
+		expected []string
+	}{
+		{name: "testFits", s: "boil the pasta", width: 14, expected: []string{"boil the pasta"}},
+		{name: "testWraps", s: "boil the pasta", width: 8, expected: []string{"boil the", "pasta"}},
+		{name: "testLongWord", s: "supercalifragilistic", width: 8, expected: []string{"supercal", "ifragili", "stic"}},
+		{name: "testLongWordAfterWords", s: "a b abcdefghij c", width: 4, expected: []string{"a b", "abcd", "efgh", "ij c"}},
+		{name: "testAccents", s: "crème brûlée", width: 6, expected: []string{"crème", "brûlée"}},
+		{name: "testCombiningMarks", s: "crèmée", width: 3, expected: []string{"crè", "mée"}},
+		{name: "testWideRunes", s: "寿司 ラーメン", width: 6, expected: []string{"寿司", "ラーメ", "ン"}},
+		{name: "testWideRuneInNarrowColumn", s: "寿司", width: 1, expected: []string{"寿", "司"}},
+		{name: "testNewlines", s: "boil\nthe pasta\r\n\ndrain", width: 10, expected: []string{"boil", "the pasta", "", "drain"}},
+		{name: "testTabs", s: "a\tb\tc", width: 20, expected: []string{"a   b   c"}},
+		{name: "testEmpty", s: "", width: 5, expected: []string{""}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			splits := splitWords(tc.s, tc.width)
+			if strings.Join(splits, "|") != strings.Join(tc.expected, "|") {
+				t.Errorf("got %q, expected %q", splits, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRenderWideCharacters(t *testing.T) {
+	var out bytes.Buffer
+	var menu Menu
+	menu.Session = NewSession(nil, &out)
+	menu.Columns = []MenuColumn{
+		{ColWidth: 3, Type: IntType, Label: "#"},
+		{ColWidth: -6, Type: StringType, Label: "名前"},
+	}
+	menu.AddCommand(&Command{Name: "寿司"})
+	menu.AddCommand(&Command{Name: "crème"})
+	menu.AddCommand(&Command{Name: "ramen"})
+
+	menu.ShowMenu()
+
+	// every row has the same display width, so the columns line up
+	for _, line := range strings.Split(strings.Trim(out.String(), "\n"), "\n") {
+		if strings.HasPrefix(line, "-") {
+			continue
+		}
+		if displayWidth(line) != 11 {
+			t.Errorf("row %q is %v columns wide, expected %v", line, displayWidth(line), 11)
+		}
+	}
+}
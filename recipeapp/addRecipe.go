@@ -23,7 +23,11 @@ func registerAddRecipeCommand(menu *climenus.Menu) {
 	menu.AddCommand(&climenus.Command{
 		Name:        "add",
 		Description: "Add Recipe",
-		Execute:     AddRecipeLoop,
+		Help: "Prompts for a recipe name, then for ingredients one at a time " +
+			"(enter 'undo' to remove the last one, 'done' when done), then for the recipe steps. " +
+			"Any of them can be changed on the review screen before the recipe is saved, " +
+			"or enter 'cancel' to leave without saving.",
+		Run: AddRecipeLoop,
 	})
 }
 
@@ -37,33 +41,6 @@ func recipeNameValidator(input string) (bool, error) {
 	return true, nil
 }
 
-// Function used as a validator for ingredient input
-// Checks that the comma delimited list is the correct length for ingredient, quantity
-// or ingredient, quantity, unit, and checks that the quantity can be pasrsed as a float
-func ingredientValidator(input string) (bool, error) {
-	if input == doneInput || input == undoIngredientInput {
-		if input == undoIngredientInput {
-			fmt.Println("last ingredient removed.")
-		}
-		return true, nil
-	}
-
-	items := strings.Split(input, ",")
-
-	// check number of args is either 2 or 3
-	if len(items) > 3 || len(items) < 2 {
-		return false, errors.New("must enter either ingredient, quantity or ingredient, quantity, unit")
-	}
-
-	// check that 2nd arg can be converted to a float for quantity value
-	_, err := strconv.ParseFloat(strings.TrimSpace(items[1]), 32)
-	if err != nil {
-		return false, errors.New("ingredient quantity must be a number")
-	}
-
-	return true, nil
-}
-
 // Validator used for recipe steps. As of now this just
 // checks that the length of the step is less than the max allowed.
 func recipeStepValidator(s string) (bool, error) {
@@ -78,16 +55,18 @@ func recipeStepValidator(s string) (bool, error) {
 }
 
 // Parses an Ingredient struct from ingredient text input from the user
+// Checks that the comma delimited list is the correct length for ingredient, quantity
+// or ingredient, quantity, unit, and that the quantity can be parsed as a float
 // returns the resulting Ingredient struct
 func parseIngredient(ingredientString string) (Ingredient, error) {
 	fields := strings.Split(ingredientString, ",")
 	if !(len(fields) == 2 || len(fields) == 3) {
-		return Ingredient{}, errors.New("")
+		return Ingredient{}, errors.New("must enter either ingredient, quantity or ingredient, quantity, unit")
 	}
 	name := strings.TrimSpace(fields[0])
 	quantity, err := strconv.ParseFloat(strings.TrimSpace(fields[1]), 32)
 	if err != nil {
-		return Ingredient{}, err
+		return Ingredient{}, errors.New("ingredient quantity must be a number")
 	}
 
 	unit := ""
@@ -97,136 +76,117 @@ func parseIngredient(ingredientString string) (Ingredient, error) {
 	return Ingredient{Name: name, Quantity: float32(quantity), Unit: unit}, nil
 }
 
-// command for saving a recipe
-const saveRecipeInput = "save"
+// names of the fields of the add recipe form
+const recipeNameField = "name"
+const ingredientsField = "ingredients"
+const stepsField = "steps"
 
-// command for done adding ingredients
-const doneInput = "done"
-
-// commmand for undoing an added ingredient
-const undoIngredientInput = "undo"
+// Parses an ingredient for the ingredients field of the add recipe form, see parseIngredient
+func parseIngredientField(input string) (interface{}, error) {
+	return parseIngredient(input)
+}
 
-// Loop used for adding a new recipe. Prompts the user for a recipe name,
-// then has the user add ingredients one at a time, and then saves the new
-// recipe to the data file when the user requests to save.
-func AddRecipeLoop(args []string, menu *climenus.Menu) error {
+// values entered in the add recipe form
+type recipeFormValues struct {
+	Name        string
+	Ingredients []Ingredient
+	Steps       []string
+}
 
-	if len(args) > 1 {
-		return errors.New("invalid command")
+// Creates the form used for adding a new recipe, returns an error if a field can't be added
+func newRecipeForm() (*climenus.Form, error) {
+	form := &climenus.Form{Title: "New recipe"}
+	fields := []*climenus.FormField{
+		{
+			Name:      recipeNameField,
+			Label:     "Recipe name",
+			Type:      climenus.TextField,
+			Required:  true,
+			Validator: recipeNameValidator,
+		},
+		{
+			Name:  ingredientsField,
+			Label: "Ingredients",
+			Help:  "in the form ingredient name, quantity, unit (optional)",
+			Type:  climenus.ListField,
+			Parse: parseIngredientField,
+		},
+		{
+			Name:      stepsField,
+			Label:     "Recipe steps",
+			Type:      climenus.ListField,
+			Validator: recipeStepValidator,
+		},
+	}
+	for _, field := range fields {
+		if err := form.AddField(field); err != nil {
+			return nil, err
+		}
 	}
+	return form, nil
+}
 
-	prompt := "Enter a name for the recipe:\n----------------------------"
-	recipeName := climenus.UserInput(prompt, recipeNameValidator)
-
-	ingredientsList := make([]Ingredient, 0)
-
-	ingredientStrings := getIngredientsInput()
-	for _, ingredientString := range ingredientStrings {
-		var ingredient Ingredient
-
-		var err error
-		ingredient, err = parseIngredient(ingredientString)
-		if err != nil {
-			fmt.Println("ingredientString:" + ingredientString)
-			fmt.Println("parseingredient:" + err.Error())
-			return err
-		}
+// Loop used for adding a new recipe. Prompts the user for a recipe name,
+// ingredients and steps with the add recipe form, then saves the new
+// recipe to the data file once the user submits the form.
+// The command takes no arguments, so input with arguments is rejected before this runs.
+func AddRecipeLoop(args *climenus.Args, menu *climenus.Menu) error {
 
-		ingredientsList = append(ingredientsList, ingredient)
+	form, err := newRecipeForm()
+	if err != nil {
+		return err
+	}
+	values, err := form.Run(menu)
+	if errors.Is(err, climenus.ErrFormCancelled) {
+		fmt.Fprintln(menu.Out(), "Recipe not saved.")
+		return nil
+	} else if err != nil {
+		return err
 	}
 
-	recipeStepStrings := getRecipeStepsInput()
+	var input recipeFormValues
+	err = values.Decode(&input)
+	if err != nil {
+		return err
+	}
 
 	recipe := Recipe{
-		Name:        recipeName,
-		Ingredients: ingredientsList,
-		Steps:       recipeStepStrings,
+		Name:        input.Name,
+		Ingredients: input.Ingredients,
+		Steps:       input.Steps,
 	}
 
-	err := saveRecipe(&recipe)
+	err = saveRecipe(&recipe, menu)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Successfully saved recipe: %v.\n", recipe.Name)
+	fmt.Fprintf(menu.Out(), "Successfully saved recipe: %v.\n", recipe.Name)
 
 	// put in a slight delay before returning to previous menu
-	time.Sleep(time.Second * 2)
+	pause(time.Second * 2)
 	return nil
 
 }
 
-func getRecipeStepsInput() []string {
-	prompt := "\nPlease add recipe steps 1 step at a time, enter done when done"
-
-	input := ""
-	recipeStepStrings := make([]string, 0)
-
-	for input != doneInput {
-
-		input = climenus.UserInput(prompt, recipeStepValidator)
-		if input != doneInput {
-			recipeStepStrings = append(recipeStepStrings, input)
-		}
-	}
-
-	return recipeStepStrings
-
-}
-
-// Function used to get user input for ingredients for the recipe.
-// Loops through user input ingredients, validates that each user input can be parsed as an ingredient,
-// then returns the slice of ingredient strings
-func getIngredientsInput() []string {
-	prompt := "\nPlease enter recipe ingredients in the following format:"
-	prompt += "Ingredient name, ingredient quantity, ingredient unit\n"
-	prompt += "(enter 'done' once done, enter 'undo' to remove last added ingredient)"
-
-	input := ""
-	ingredientStrings := make([]string, 0)
-	for input != doneInput {
-
-		input = climenus.UserInput(prompt, ingredientValidator)
-		if input == undoIngredientInput {
-			n := len(ingredientStrings)
-			if n > 0 {
-				ingredientStrings = ingredientStrings[:n-1]
-				fmt.Println("removed last ingredient")
-			}
-		} else if input != doneInput {
-			ingredientStrings = append(ingredientStrings, input)
-		}
-	}
-
-	return ingredientStrings
-}
-
-// Function used for validating input is either Y or N
-func yesNoValidator(input string) (bool, error) {
-	if !(strings.ToLower(input) == "y" || strings.ToLower(input) == "n") {
-		return false, errors.New("must enter either 'Y' or 'N'")
-	}
-
-	return true, nil
-}
-
 // Saves the recipe that is currently being added to the stored recipe data
-func saveRecipe(recipe *Recipe) error {
+func saveRecipe(recipe *Recipe, menu *climenus.Menu) error {
 	overwrite := false
 	err := addRecipe((*recipe), jsonFileName, overwrite)
 
 	if errors.Is(err, errRecipeAlreadyExists) {
 		prompt := fmt.Sprintf("A recipe with name %s already exists. Overwrite this recipe? (Y/N)\n", recipe.Name)
-		choice := strings.ToLower(climenus.UserInput(prompt, yesNoValidator))
+		overwrite, err = climenus.Prompt(menu, prompt, climenus.ParseYesNo)
+		if err != nil {
+			return err
+		}
 
-		if choice == "n" {
+		if !overwrite {
 			return errors.New("aborted creating new recipe due to conflicting recipe name")
-		} else if choice == "y" {
-			overwrite = true
-			err = addRecipe((*recipe), jsonFileName, overwrite)
-			if err != nil {
-				return err
-			}
+		}
+		err = addRecipe((*recipe), jsonFileName, overwrite)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"strconv"
 	"time"
 
 	"github.com/dulshen/goproject/climenus"
@@ -14,47 +13,61 @@ const delName = "del"
 // menu description for delete recipe
 const delDescr = "Delete Recipe"
 
-// Registers the delete recipe command in the main menu
+// Registers the delete recipe command in the main menu. The command opens the select recipe
+// menu, which executes the deleteRecipe function for the recipe selected by the user
 func registerDeleteRecipeCommand(menu *climenus.Menu) {
-	menu.AddCommand(&climenus.Command{Name: delName, Description: delDescr, Execute: deleteRecipeLoop})
-}
-
-// Main loop for deleting a recipe. Uses the select recipe loop to get a recipe selection
-// from the user, and then executes the deleteRecipe function
-func deleteRecipeLoop(args []string, menu *climenus.Menu) error {
 	instructions := "Please choose a recipe to delete\n" +
 		"---------------------------------"
-	err := selectRecipeLoop(deleteRecipe, instructions)
-	if err != nil {
-		return err
+	initializeCommands := func(menu *climenus.Menu, recipes *[]Recipe) error {
+		return InitializeSelectRecipeCommands(menu, recipes, deleteRecipe)
 	}
+	selectMenu := newSelectRecipeMenu("Delete", instructions, initializeCommands)
+	selectMenu.Middleware = []climenus.Middleware{confirmDelete}
 
-	return nil
+	menu.AddCommand(&climenus.Command{
+		Name:        delName,
+		Description: delDescr,
+		Help:        "Lists the stored recipes, selecting one deletes it from the recipe data once confirmed.",
+		SubMenu:     selectMenu,
+	})
 }
 
-// Removes the recipe indicated by the index provided in args from the stored recipe data
-func deleteRecipe(args []string, menu *climenus.Menu) error {
+// Middleware for the delete select menu asking for confirmation before a recipe
+// is deleted, the recipe is kept if it isn't confirmed
+func confirmDelete(next climenus.Handler) climenus.Handler {
+	return func(command *climenus.Command, args *climenus.Args, menu *climenus.Menu) error {
+		// recipes are the unnamed commands, "back" isn't confirmed
+		if command.Name != "" {
+			return next(command, args, menu)
+		}
 
-	selectionInt, err := strconv.Atoi(args[0])
-	if err != nil {
-		return err
+		prompt := fmt.Sprintf("Delete %s? (y/n)", command.Description)
+		confirmed, err := climenus.Prompt(menu, prompt, climenus.ParseYesNo)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(menu.Out(), "Recipe not deleted.")
+			return nil
+		}
+		return next(command, args, menu)
 	}
+}
 
-	index := selectionInt - 1
-	err = removeRecipe(index, jsonFileName)
-	if err != nil {
-		return err
-	}
+// Removes the recipe indicated by the option number of the selected command from the stored recipe data
+// the select recipe menu is refreshed from the stored data when it is shown again
+func deleteRecipe(args *climenus.Args, menu *climenus.Menu) error {
 
-	fmt.Printf("Succesfully deleted recipe %s\n", args[0])
-	time.Sleep(1 * time.Second)
+	selectionInt := args.Command.OptionNumber
 
-	recipes, err := readRecipesJSON(jsonFileName)
+	index := selectionInt - 1
+	err := removeRecipe(index, jsonFileName)
 	if err != nil {
 		return err
 	}
-	// re-initialize the select recipe menu commands list
-	InitializeSelectRecipeCommands(menu, recipes, deleteRecipe)
+
+	fmt.Fprintf(menu.Out(), "Succesfully deleted recipe %d\n", selectionInt)
+	pause(1 * time.Second)
 
 	return nil
 }
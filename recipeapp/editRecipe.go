@@ -3,18 +3,26 @@ package main
 import (
 	"errors"
 	"fmt"
-	"strconv"
-	"strings"
+	"log"
 	"time"
 
 	"github.com/dulshen/goproject/climenus"
 )
 
+// menu name for edit recipe
+const editName = "edit"
+
 // Struct used for passing necessary data for selected recipe
 // to a climenus.Menu struct, so that this can be accessed by other functions later
 type editARecipeMenuData struct {
-	Recipe    *Recipe // pointer to the recipe for this menu
-	RecipeIdx int     // int indicating the index of this recipe in the list of Recipes in storage
+	Recipe    *Recipe             // pointer to the recipe for this menu
+	RecipeIdx int                 // int indicating the index of this recipe in the list of Recipes in storage
+	Edits     *editSelectMenuData // data of the select recipe menu this menu was opened from
+}
+
+// Struct stored in menu.Data of the select recipe menu for editing
+type editSelectMenuData struct {
+	Unsaved *editARecipeMenuData // recipe being edited with changes that haven't been saved, nil if there are none
 }
 
 // const recipeNameIdx = "recipe name index"
@@ -22,78 +30,65 @@ type editARecipeMenuData struct {
 // const ingredentsEndIdx = "ingredients end index"
 
 // function used to add the Edit Recipe command to the main menu
+// the command opens the select recipe menu, where selecting a recipe
+// opens the edit a recipe menu for that recipe
 func registerEditRecipeCommand(menu *climenus.Menu) {
-	menu.AddCommand(&climenus.Command{Name: "edit", Description: "Edit a Recipe", Execute: editRecipesLoop})
-}
-
-// Main loop for the edit recipes menu, asks the user to select a recipe
-// then calls the edit a recipe menu, passing the selected recipe in args
-func editRecipesLoop(args []string, menu *climenus.Menu) error {
 	instructions := "Please choose a recipe to edit\n" +
 		"---------------------------------"
-
-	err := selectRecipeLoop(editRecipe, instructions)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	selectMenu := newSelectRecipeMenu("Edit", instructions, initializeEditSelectCommands)
+	selectMenu.Data = &editSelectMenuData{}
+
+	menu.AddCommand(&climenus.Command{
+		Name:        editName,
+		Description: "Edit a Recipe",
+		Help: "Lists the stored recipes, selecting one shows its name, ingredients and steps, " +
+			"which can be changed by selecting them. Changes are only stored once saved.",
+		Examples: []string{"goto edit/1"},
+		SubMenu:  selectMenu,
+	})
 }
 
-// Determines which recipe the user selected by parsing the selection in args
-// then initializes a menu with options for editing this recipe
-// and calls the menu loop for editing this recipe
-func editRecipe(args []string, menu *climenus.Menu) error {
-	chosenRecipeNum, err := strconv.Atoi(strings.TrimSpace(args[0]))
-	if err != nil {
-		return err
-	}
-
-	recipes, err := readRecipesJSON(jsonFileName)
-	if err != nil {
-		return err
+// Initializes the select recipe menu for editing with a command for each recipe in the recipe data
+// each command has the edit a recipe menu for its recipe as a SubMenu
+func initializeEditSelectCommands(menu *climenus.Menu, recipes *[]Recipe) error {
+	menu.Commands = []*climenus.Command{}
+	menu.CommandsMap = map[string]*climenus.Command{}
+	// the recipes are read again from storage, so any changes that weren't saved are dropped
+	edits, ok := menu.Data.(*editSelectMenuData)
+	if !ok {
+		return errors.New("type assertion failed: edit select menu data is not in correct form")
 	}
+	edits.Unsaved = nil
 
-	index := chosenRecipeNum - 1
-	recipe := &((*recipes)[index])
-
-	editThisRecipeMenu := initializeEditARecipeMenu(recipe, index)
-
-	err = editThisRecipeMenu.MenuLoop()
-	if err != nil {
-		return err
+	for index := range *recipes {
+		recipe := &((*recipes)[index])
+		editThisRecipeMenu := initializeEditARecipeMenu(recipe, index, edits)
+		menu.AddCommand(&climenus.Command{Description: recipe.Name, Name: "", SubMenu: editThisRecipeMenu})
 	}
 
-	// on exiting this menu need to re-read recipes to see if any chnages were saved
-	recipes, err = readRecipesJSON(jsonFileName)
-	if err != nil {
-		return err
-	}
-	// re-initialize the parent menu before it is re-displayed, in case of changes
-	err = InitializeSelectRecipeCommands(menu, recipes, editRecipe)
-	if err != nil {
-		fmt.Println(err.Error())
-		return err
-	}
+	menu.AddCommand(&climenus.Command{Name: "back", Description: "", Execute: climenus.BackFunc})
 
 	return nil
-
 }
 
 // Initializes edit a recipe menu for the selected recipe
 // sets the menu instructions, the column widths and types, and passes the recipe data and index
-// to a struct stored in menu.Data, then calls another function to initialize the commands for the menu
+// to a struct stored in menu.Data, along with the data of the select menu that records unsaved edits,
+// then calls another function to initialize the commands for the menu
 // returns the initialized menu for editing this recipe
-func initializeEditARecipeMenu(recipe *Recipe, index int) *climenus.Menu {
+func initializeEditARecipeMenu(recipe *Recipe, index int, edits *editSelectMenuData) *climenus.Menu {
 	var menu climenus.Menu
 
 	menu.Instructions = "Choose an item from the recipe to edit:"
 	c1 := climenus.MenuColumn{ColWidth: 5, Type: climenus.StringType, Label: optionNumberLabel}
 	c2 := climenus.MenuColumn{ColWidth: -5, Type: climenus.StringType, Label: commandNameLabel}
-	c3 := climenus.MenuColumn{ColWidth: -40, Type: climenus.StringType, Label: descriptionLabel}
+	// ingredients and steps fill the width of the terminal
+	c3 := climenus.MenuColumn{
+		MinWidth: minRecipeTextColWidth, Align: climenus.AlignLeft, Type: climenus.StringType, Label: descriptionLabel,
+	}
 	menu.Columns = append(menu.Columns, c1, c2, c3)
 
-	menu.Data = &(editARecipeMenuData{Recipe: recipe, RecipeIdx: index})
+	menu.Data = &(editARecipeMenuData{Recipe: recipe, RecipeIdx: index, Edits: edits})
 	initializeEditRecipeCommands(&menu, recipe)
 
 	return &menu
@@ -108,12 +103,11 @@ func initializeEditRecipeCommands(menu *climenus.Menu, recipe *Recipe) error {
 	menu.AddCommand(&climenus.Command{Name: "", Description: "Recipe Name: " + recipe.Name, Execute: editRecipeName})
 
 	for _, ingredient := range recipe.Ingredients {
-		ingredientStr := fmt.Sprintf("%v, %v, %v", ingredient.Name, ingredient.Quantity, ingredient.Unit)
-		menu.AddCommand(&climenus.Command{Name: "", Description: ingredientStr, Execute: editRecipeIngredient})
+		menu.AddCommand(&climenus.Command{Name: "", Description: ingredient.String(), Run: editRecipeIngredient})
 	}
 
 	for _, step := range recipe.Steps {
-		menu.AddCommand(&climenus.Command{Name: "", Description: step, Execute: editRecipeStep})
+		menu.AddCommand(&climenus.Command{Name: "", Description: step, Run: editRecipeStep})
 	}
 
 	menu.AddCommand(&climenus.Command{Name: "add", Description: "Add an Ingredient", Execute: addIngredient})
@@ -132,9 +126,14 @@ func editRecipeName(args []string, menu *climenus.Menu) error {
 	}
 
 	prompt := "Provide a new name for this recipe:"
-	input := climenus.UserInput(prompt, recipeNameValidator)
+	input, err := menu.UserInputErr(prompt, recipeNameValidator)
+	if err != nil {
+		return err
+	}
 	recipe.Name = input
 
+	markUnsaved(menu)
+
 	// re-initialize edit a recipe commands in case options changed
 	initializeEditRecipeCommands(menu, recipe)
 
@@ -144,15 +143,9 @@ func editRecipeName(args []string, menu *climenus.Menu) error {
 // Function used for editing a recipe ingredient. Takes user input for an updated
 // ingredient to use for the selected ingredient (indicated by args), and stores this
 // in the ingredients list for the recipe being edited currently
-func editRecipeIngredient(args []string, menu *climenus.Menu) error {
-	// args[0] will be the option number chosen
+func editRecipeIngredient(args *climenus.Args, menu *climenus.Menu) error {
 	// idx 1 is recipe name, so recipe ingredients start at idx 2 so adjust by 2 to get 0-indexed
-	ingredientIdx, err := strconv.Atoi(args[0])
-	ingredientIdx -= 2
-
-	if err != nil {
-		return err
-	}
+	ingredientIdx := args.Command.OptionNumber - 2
 
 	recipe, _, err := extractRecipeData(menu)
 	if err != nil {
@@ -160,15 +153,14 @@ func editRecipeIngredient(args []string, menu *climenus.Menu) error {
 	}
 
 	prompt := "Provide new data for this ingredient (in the form ingredient name, quantity, unit(optional)):"
-	input := climenus.UserInput(prompt, ingredientValidator)
-
-	ingredient, err := parseIngredient(input)
+	ingredient, err := climenus.Prompt(menu, prompt, parseIngredient)
 	if err != nil {
 		return err
 	}
-
 	recipe.Ingredients[ingredientIdx] = ingredient
 
+	markUnsaved(menu)
+
 	// re-initialize edit a recipe commands in case options changed
 	initializeEditRecipeCommands(menu, recipe)
 
@@ -179,26 +171,28 @@ func editRecipeIngredient(args []string, menu *climenus.Menu) error {
 // Uses the provided arg to get the index of the recipe step to edit
 // then prompts the user to give new text to use for this step, and replaces
 // the old step in the recipe with this updated step.
-func editRecipeStep(args []string, menu *climenus.Menu) error {
+func editRecipeStep(args *climenus.Args, menu *climenus.Menu) error {
 	recipe, _, err := extractRecipeData(menu)
 	if err != nil {
 		return err
 	}
 
-	recipeStepIdx, err := strconv.Atoi(args[0])
-	if err != nil {
-		return err
-	}
+	recipeStepIdx := args.Command.OptionNumber
 	// ingredients start at # 2
 	// so recipeStep starts at 2 + len(ingredients)
 
 	recipeStepIdx = recipeStepIdx - len(recipe.Ingredients) - 2
 
 	prompt := "Provide new data for this recipe step:"
-	input := climenus.UserInput(prompt, recipeStepValidator)
+	input, err := menu.UserInputErr(prompt, recipeStepValidator)
+	if err != nil {
+		return err
+	}
 
 	recipe.Steps[recipeStepIdx] = input
 
+	markUnsaved(menu)
+
 	// re-initialize edit a recipe commands in case options changed
 	initializeEditRecipeCommands(menu, recipe)
 	return nil
@@ -214,14 +208,15 @@ func addIngredient(args []string, menu *climenus.Menu) error {
 
 	prompt := "\nPlease enter recipe ingredients in the following format:"
 	prompt += "Ingredient name, ingredient quantity, ingredient unit\n"
-	input := climenus.UserInput(prompt, ingredientValidator)
-	ingredient, err := parseIngredient(input)
+	ingredient, err := climenus.Prompt(menu, prompt, parseIngredient)
 	if err != nil {
 		return err
 	}
 
 	recipe.Ingredients = append(recipe.Ingredients, ingredient)
 
+	markUnsaved(menu)
+
 	// re-initialize edit a recipe commands in case options changed
 	initializeEditRecipeCommands(menu, recipe)
 
@@ -240,12 +235,13 @@ func saveChanges(args []string, menu *climenus.Menu) error {
 
 	err = replaceRecipe(*recipe, jsonFileName, recipeIdx)
 	if err != nil {
-		fmt.Println(err.Error())
+		fmt.Fprintln(menu.Out(), err.Error())
 		return err
 	}
 
-	fmt.Printf("Successfully saved changes to %s\n", recipe.Name)
-	time.Sleep(1 * time.Second)
+	markSaved(menu)
+	fmt.Fprintf(menu.Out(), "Successfully saved changes to %s\n", recipe.Name)
+	pause(1 * time.Second)
 
 	return nil
 }
@@ -263,3 +259,39 @@ func extractRecipeData(menu *climenus.Menu) (*Recipe, int, error) {
 
 	return recipe, idx, nil
 }
+
+// Records that the recipe edited with this edit a recipe menu has changes that haven't been saved
+func markUnsaved(menu *climenus.Menu) {
+	if menuData, ok := menu.Data.(*editARecipeMenuData); ok && menuData.Edits != nil {
+		menuData.Edits.Unsaved = menuData
+	}
+}
+
+// Records that the changes to the recipe edited with this edit a recipe menu have been saved
+func markSaved(menu *climenus.Menu) {
+	if menuData, ok := menu.Data.(*editARecipeMenuData); ok && menuData.Edits != nil {
+		menuData.Edits.Unsaved = nil
+	}
+}
+
+// Saves the changes to the recipe being edited from the main menu's edit command if they
+// haven't been saved, run on shutdown so edits aren't lost when the program is ended by a signal
+func saveUnsavedEdits(mainMenu *climenus.Menu) {
+	command, ok := mainMenu.CommandsMap[editName]
+	if !ok || command.SubMenu == nil {
+		return
+	}
+	edits, ok := command.SubMenu.Data.(*editSelectMenuData)
+	if !ok || edits.Unsaved == nil {
+		return
+	}
+
+	recipe := edits.Unsaved.Recipe
+	err := replaceRecipe(*recipe, jsonFileName, edits.Unsaved.RecipeIdx)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	edits.Unsaved = nil
+	fmt.Fprintf(mainMenu.Out(), "Saved unsaved changes to %s\n", recipe.Name)
+}
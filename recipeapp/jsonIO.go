@@ -15,6 +15,9 @@ const jsonFileName = "../data/recipes.json"
 // string representing the directory json data is stored in
 const jsonDirectoryName = "../data"
 
+// string representing the filename to store menu input history
+const historyFileName = "../data/history"
+
 // checks if the directory and json file for storing data are set up yet
 // and creates the directory and file if needed, to prevent errors later
 // on when writing data to json file
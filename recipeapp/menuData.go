@@ -1,10 +1,22 @@
 package main
 
+import "time"
+
 const exit = "exit"
 
+// waits before going back to the previous menu, so messages can be read. Replaced in tests
+var pause = time.Sleep
+
 const optionNumberColWidth = 2
 const commandNameColWidth = 5
 const descriptionColWidth = 20
+const minRecipeTextColWidth = 20
 const optionNumberLabel = "#"
 const commandNameLabel = "Name"
 const descriptionLabel = "Description"
+
+// number of recipes shown at once when selecting a recipe
+const selectRecipePageSize = 20
+
+// hint added to the instructions of the select recipe menus
+const selectRecipeFilterHint = "\nEnter /text to show only recipes containing text"
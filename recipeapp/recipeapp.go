@@ -1,7 +1,11 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/dulshen/goproject/climenus"
 )
@@ -24,29 +28,114 @@ type Ingredient struct {
 	Unit     string  // unit of quantity
 }
 
+// returns the ingredient as it is entered, ingredient, quantity, unit
+func (ingredient Ingredient) String() string {
+	return fmt.Sprintf("%v, %v, %v", ingredient.Name, ingredient.Quantity, ingredient.Unit)
+}
+
 func registerExitCommand(menu *climenus.Menu) {
-	menu.AddCommand(&climenus.Command{Name: exit, Description: "Exit Program", Execute: climenus.ExitFunc})
+	menu.AddCommand(&climenus.Command{Name: exit, Aliases: []string{"quit"}, Description: "Exit Program", Execute: climenus.ExitFunc})
 }
 
 // Starts the program
 // initializes the json data storage file if needed, then runs the main menu's loop
+// using a line editor for input, which keeps input history in the data directory.
+// With -script the inputs are read from a file instead (or stdin for "-"), and the
+// program exits with an error at the first input that isn't accepted. With -record
+// the session is recorded to a transcript file, which can be replayed as a test.
+// Otherwise Ctrl+C and SIGTERM are trapped, saving any unsaved edits before exiting
 func main() {
+	scriptName := flag.String("script", "", "file of inputs to run instead of prompting, - for stdin")
+	recordName := flag.String("record", "", "file to record a transcript of the session to")
+	flag.Parse()
 
 	initializeJSONFile(jsonFileName, jsonDirectoryName, false)
 
 	log.SetPrefix("climenu: ")
 	log.SetFlags(0)
 
+	session := climenus.NewSession(os.Stdin, os.Stdout)
+	session.Editor = climenus.NewLineEditor(historyFileName)
+	// Ctrl+D goes back a menu (or leaves a prompt), and the end of piped input exits
+	session.OnEOF = climenus.EOFBack
+	if *recordName != "" {
+		stopRecording := recordSession(session, *recordName)
+		defer stopRecording()
+	}
+
+	// a command that panics prints an error instead of ending the program mid-edit
+	climenus.Use(climenus.Recover())
+
 	mainMenu := initializeMenu()
-	mainMenu.MenuLoop()
+	mainMenu.Session = session
+	// the menu is built the same way every run, so a mistake in it is a bug
+	if err := mainMenu.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	if *scriptName != "" {
+		runScript(mainMenu, *scriptName)
+		return
+	}
+	// Ctrl+C cancels a prompt or goes back a menu, and edits that haven't been
+	// saved are saved if the program is ended by a signal
+	stopTrapping := session.TrapSignals()
+	defer stopTrapping()
+	session.OnShutdown(func() { saveUnsavedEdits(mainMenu) })
+
+	err := mainMenu.MenuLoop()
+	if err != nil && !errors.Is(err, climenus.ErrExitProgram) {
+		log.Print(err)
+	}
 
 }
 
+// Starts recording the session to a transcript in the named file,
+// returns a function that finishes the transcript
+func recordSession(session *climenus.Session, name string) func() {
+	file, err := os.Create(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	recorder := session.Record(file)
+	return func() {
+		err := recorder.Close()
+		if err == nil {
+			err = file.Close()
+		}
+		if err != nil {
+			log.Print(err)
+		}
+	}
+}
+
+// Runs the inputs in the named script file, or stdin for "-", exiting with an error
+// if the script stops at an input that isn't accepted
+func runScript(mainMenu *climenus.Menu, name string) {
+	var err error
+	if name == "-" {
+		err = climenus.RunScript(mainMenu, os.Stdin)
+	} else {
+		err = climenus.RunScriptFile(mainMenu, name)
+	}
+	if err != nil && !errors.Is(err, climenus.ErrExitProgram) {
+		log.Fatal(err)
+	}
+}
+
 // Initializes the main menu, setting up the columns and registering the commands
 func initializeMenu() *climenus.Menu {
 	var menu climenus.Menu
 
 	menu.Instructions = mainMenuInstructions
+	// main menu commands can be entered in any case or shortened, e.g. "V" for view,
+	// and Enter accepts the suggestion for a mistyped command
+	menu.CaseInsensitive = true
+	menu.MatchPrefixes = true
+	menu.AcceptSuggestions = true
+	// colors are left out when output isn't a terminal or NO_COLOR is set
+	menu.Theme = climenus.DefaultTheme
+	menu.Border = climenus.RoundedBorder
 
 	optionNumberCol := climenus.MenuColumn{
 		ColWidth: optionNumberColWidth,
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dulshen/goproject/climenus"
+)
+
+var update = flag.Bool("update", false, "update the golden transcripts in testdata")
+
+// recipes stored when each transcript is recorded or replayed
+var testRecipes = []Recipe{
+	{Name: "Pasta", Ingredients: []Ingredient{{"flour", 2, "cups"}, {"egg", 3, ""}}, Steps: []string{"boil water"}},
+	{Name: "Toast", Ingredients: []Ingredient{{"bread", 1, "slice"}}, Steps: []string{"toast the bread"}},
+}
+
+// sessions with the real menus, recorded to testdata/<name>.transcript with -update
+var transcriptTests = []struct {
+	name     string
+	inputs   []string
+	expected []string // names of the recipes stored afterwards
+}{
+	{
+		name: "add",
+		inputs: []string{
+			"add", "Soup",
+			"water", "water, 2, cups", "salt, 1", "pepper, 1", "undo", "done",
+			"boil the water", "done",
+			"2", "salt, 2, pinches", "done", "submit",
+			"exit",
+		},
+		expected: []string{"Pasta", "Toast", "Soup"},
+	},
+	{
+		name: "edit",
+		inputs: []string{
+			"edit", "1",
+			"1", "Fresh pasta", "2", "flour, 3, cups", "add", "salt, 1", "save",
+			"back", "2", "3", "toast and butter the bread", "back",
+			"back", "exit",
+		},
+		expected: []string{"Fresh pasta", "Toast"},
+	},
+	{
+		name:     "view",
+		inputs:   []string{"view", "1", "scale", "scale lots", "scale 2", "back", "back", "exit"},
+		expected: []string{"Pasta", "Toast"},
+	},
+	{
+		name:     "delete",
+		inputs:   []string{"del", "1", "n", "2", "y", "back", "exit"},
+		expected: []string{"Pasta"},
+	},
+}
+
+// stores testRecipes in a temporary data directory, and changes to a directory next
+// to it so the data paths, which are relative to the working directory, lead to it
+func useTestData(t *testing.T) {
+	dir := t.TempDir()
+	work := filepath.Join(dir, "work")
+	for _, d := range []string{work, filepath.Join(dir, "data")} {
+		if err := os.Mkdir(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recipes := append([]Recipe(nil), testRecipes...)
+	if err := writeRecipesJSON(filepath.Join(dir, "data", "recipes.json"), &recipes); err != nil {
+		t.Fatal(err)
+	}
+
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(work); err != nil {
+		t.Fatal(err)
+	}
+	pause = func(time.Duration) {}
+	t.Cleanup(func() {
+		os.Chdir(previous)
+		pause = time.Sleep
+	})
+}
+
+// returns the names of the stored recipes
+func storedRecipeNames(t *testing.T) []string {
+	recipes, err := readRecipesJSON(jsonFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, recipe := range *recipes {
+		names = append(names, recipe.Name)
+	}
+	return names
+}
+
+// records a session of the main menu with the inputs as a transcript
+func recordTranscript(t *testing.T, inputs []string) string {
+	var out, transcript bytes.Buffer
+	menu := initializeMenu()
+	menu.Session = climenus.NewSession(strings.NewReader(strings.Join(inputs, "\n")+"\n"), &out)
+	menu.Session.Width = 80
+	menu.Session.Color = climenus.ColorNever
+
+	recorder := menu.Session.Record(&transcript)
+	err := menu.MenuLoop()
+	if !errors.Is(err, climenus.ErrExitProgram) {
+		t.Fatalf("got error %v, expected the program to exit", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return transcript.String()
+}
+
+func TestMainMenuValidates(t *testing.T) {
+	if err := initializeMenu().Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewRecipeForm(t *testing.T) {
+	if _, err := newRecipeForm(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTranscripts(t *testing.T) {
+	for _, tc := range transcriptTests {
+		t.Run(tc.name, func(t *testing.T) {
+			golden, err := filepath.Abs(filepath.Join("testdata", tc.name+".transcript"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *update {
+				useTestData(t)
+				transcript := recordTranscript(t, tc.inputs)
+				if err := os.WriteFile(golden, []byte(transcript), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			recorded, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			useTestData(t)
+			_, err = climenus.ReplayTranscript(initializeMenu(), bytes.NewReader(recorded))
+			if err != nil {
+				t.Errorf("replay of %s doesn't match: %v", golden, err)
+			}
+			names := storedRecipeNames(t)
+			if fmt.Sprint(names) != fmt.Sprint(tc.expected) {
+				t.Errorf("got recipes %v stored, expected %v", names, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSaveUnsavedEdits(t *testing.T) {
+	useTestData(t)
+	var out bytes.Buffer
+	menu := initializeMenu()
+	menu.Session = climenus.NewSession(strings.NewReader("edit\n2\n1\nFrench toast\n"), &out)
+
+	// input ends with the rename unsaved, as when the program is ended by a signal
+	err := menu.MenuLoop()
+	if !errors.Is(err, climenus.ErrEndOfInput) {
+		t.Fatalf("got error %v, expected input to end", err)
+	}
+	if names := storedRecipeNames(t); fmt.Sprint(names) != "[Pasta Toast]" {
+		t.Fatalf("got recipes %v stored before shutdown", names)
+	}
+
+	out.Reset()
+	saveUnsavedEdits(menu)
+	if names := storedRecipeNames(t); fmt.Sprint(names) != "[Pasta French toast]" {
+		t.Errorf("got recipes %v stored, expected the rename to be saved", names)
+	}
+	if out.String() != "Saved unsaved changes to French toast\n" {
+		t.Errorf("got output %q", out.String())
+	}
+
+	// nothing is left to save once it has been saved
+	out.Reset()
+	saveUnsavedEdits(menu)
+	if out.Len() != 0 {
+		t.Errorf("got output %q, expected nothing to be saved again", out.String())
+	}
+}
@@ -4,44 +4,47 @@ import (
 	"github.com/dulshen/goproject/climenus"
 )
 
-// The main loop for selecting a recipe, used by the view recipe, edit recipe, and delete recipe functions.
-// Prints a list of recipes for the user to select from, then calls the appropriate function (view, edit, delete)
-// as indicated by the executeFunc argument, with the selected recipe index as an argument
-func selectRecipeLoop(executeFunc func([]string, *climenus.Menu) error, instructions string) error {
+// Creates the menu for selecting a recipe, used as the SubMenu of the view recipe, edit recipe,
+// and delete recipe commands. The menu's commands are rebuilt from the stored recipe data
+// each time the menu is shown, by calling initializeCommands with the list of recipes
+func newSelectRecipeMenu(
+	title string, instructions string, initializeCommands func(*climenus.Menu, *[]Recipe) error,
+) *climenus.Menu {
 	var menu climenus.Menu
-	recipes, err := readRecipesJSON(jsonFileName)
-	if err != nil {
-		return err
-	}
-
-	InitializeSelectRecipeCommands(&menu, recipes, executeFunc)
 
 	c1 := climenus.MenuColumn{ColWidth: 5, Label: "#", Type: "string"}
 	c2 := climenus.MenuColumn{ColWidth: 4, Label: "", Type: "string"}
-	c3 := climenus.MenuColumn{ColWidth: -30, Label: "Recipe Name", Type: "string"}
+	// recipe names fill the width of the terminal
+	c3 := climenus.MenuColumn{MinWidth: minRecipeTextColWidth, Align: climenus.AlignLeft, Label: "Recipe Name", Type: "string"}
 
 	menu.Columns = append(menu.Columns, c1, c2, c3)
 
-	menu.Instructions = instructions
-
-	err = menu.MenuLoop()
-	if err != nil {
-		return err
+	menu.Title = title
+	menu.Instructions = instructions + selectRecipeFilterHint
+	// show recipes a page at a time so long recipe lists don't scroll off screen
+	menu.PageSize = selectRecipePageSize
+	menu.Refresh = func(menu *climenus.Menu) error {
+		recipes, err := readRecipesJSON(jsonFileName)
+		if err != nil {
+			return err
+		}
+
+		return initializeCommands(menu, recipes)
 	}
 
-	return nil
-
+	return &menu
 }
 
 // Initializes the select recipe menu with commands for each recipe in the recipe data
+// selecting a recipe calls runFunc, with the selected recipe command in args
 func InitializeSelectRecipeCommands(
-	menu *climenus.Menu, recipes *[]Recipe, executeFunc func([]string, *climenus.Menu) error,
+	menu *climenus.Menu, recipes *[]Recipe, runFunc func(*climenus.Args, *climenus.Menu) error,
 ) error {
 	menu.Commands = []*climenus.Command{}
 	menu.CommandsMap = map[string]*climenus.Command{}
 
 	for _, recipe := range *recipes {
-		menu.AddCommand(&climenus.Command{Description: recipe.Name, Name: "", Execute: executeFunc})
+		menu.AddCommand(&climenus.Command{Description: recipe.Name, Name: "", Run: runFunc})
 	}
 
 	menu.AddCommand(&climenus.Command{Name: "back", Description: "", Execute: climenus.BackFunc})
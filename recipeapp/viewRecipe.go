@@ -1,8 +1,8 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/dulshen/goproject/climenus"
@@ -17,35 +17,40 @@ const viewDescr = "View a Recipe"
 // max size for a row of text for recipe steps
 const maxStepLineSize = 50
 
-// Function used to register the view recipe command in the main menu
-func registerViewRecipeCommand(menu *climenus.Menu) {
-	c := climenus.Command{Name: viewName, Description: viewDescr, Execute: viewRecipeLoop}
-	menu.AddCommand(&c)
+// command taken at the prompt after a recipe is viewed, scaling the recipe by a factor
+var scaleCommand = &climenus.Command{
+	Name:     "scale",
+	ArgSpecs: []climenus.ArgSpec{{Name: "factor", Type: climenus.FloatType, Required: true}},
 }
 
-// Main loop for the view recipe menu
-// Makes use of the select recipe loop, which prompts the user
+// command taken at the prompt after a recipe is viewed, returning to the previous menu
+var viewBackCommand = &climenus.Command{Name: "back"}
+
+// Function used to register the view recipe command in the main menu
+// The command opens the select recipe menu, which prompts the user
 // to select a recipe to view.
-func viewRecipeLoop(args []string, menu *climenus.Menu) error {
+func registerViewRecipeCommand(menu *climenus.Menu) {
 	instructions := "Please choose a recipe to view" +
 		"---------------------------------"
-	err := selectRecipeLoop(viewRecipe, instructions)
-	if err != nil {
-		return err
+	initializeCommands := func(menu *climenus.Menu, recipes *[]Recipe) error {
+		return InitializeSelectRecipeCommands(menu, recipes, viewRecipe)
 	}
+	selectMenu := newSelectRecipeMenu("View", instructions, initializeCommands)
 
-	return nil
+	c := climenus.Command{
+		Name:        viewName,
+		Description: viewDescr,
+		Help:        "Lists the stored recipes, selecting one shows its ingredients and steps and lets you scale it.",
+		SubMenu:     selectMenu,
+	}
+	menu.AddCommand(&c)
 }
 
-// Views the recipe chosen by the user, which is indicated by the index number
-// passed in args. Prints the recipe name, and all of the recipe ingredients.
-func viewRecipe(args []string, menu *climenus.Menu) error {
+// Views the recipe chosen by the user, which is indicated by the option number
+// of the selected command. Prints the recipe name, and all of the recipe ingredients.
+func viewRecipe(args *climenus.Args, menu *climenus.Menu) error {
 
-	chosenRecipeNum, err := strconv.Atoi(strings.TrimSpace(args[0]))
-
-	if err != nil {
-		return err
-	}
+	chosenRecipeNum := args.Command.OptionNumber
 
 	index := chosenRecipeNum - 1
 	recipe, err := getRecipe(index, jsonFileName)
@@ -53,63 +58,75 @@ func viewRecipe(args []string, menu *climenus.Menu) error {
 		return err
 	}
 
-	fmt.Printf("\nRecipe: %s\n", recipe.Name)
-	fmt.Println("----------------------------------")
+	out := menu.Out()
+	fmt.Fprintf(out, "\nRecipe: %s\n", recipe.Name)
+	fmt.Fprintln(out, "----------------------------------")
 
 	for _, ingredient := range recipe.Ingredients {
-		fmt.Printf("%s: %.2f %s\n", ingredient.Name, ingredient.Quantity, ingredient.Unit)
+		fmt.Fprintf(out, "%s: %.2f %s\n", ingredient.Name, ingredient.Quantity, ingredient.Unit)
 	}
 
-	fmt.Println("----------------------------------")
+	fmt.Fprintln(out, "----------------------------------")
 
 	for i, recipeStep := range recipe.Steps {
 		stepSplits := getRecipeStepSplits(recipeStep, maxStepLineSize)
 		stepNo := i + 1
-		fmt.Println("--")
-		fmt.Printf("%d: ", stepNo)
+		fmt.Fprintln(out, "--")
+		fmt.Fprintf(out, "%d: ", stepNo)
 		for _, split := range stepSplits {
-			fmt.Printf("%s\n", split)
+			fmt.Fprintf(out, "%s\n", split)
 		}
 	}
 
-	fmt.Println("--")
-	fmt.Println("----------------------------------")
-
-	fmt.Print("\n\n")
-
-	bypassValidator := func(string) (bool, error) { return true, nil }
-	input := ""
-	for input != "back" {
-		input = climenus.UserInput("Enter 'back' to return to previous menu, "+
-			"or 'scale X' to scale recipe by X", bypassValidator)
-		args := strings.Split(input, " ")
-		if args[0] == "scale" {
-			scaledRecipeString, err := scaleRecipe(&recipe, args[1])
-			if err != nil {
-				return err
-			}
-			fmt.Println(scaledRecipeString)
+	fmt.Fprintln(out, "--")
+	fmt.Fprintln(out, "----------------------------------")
+
+	fmt.Fprint(out, "\n\n")
+
+	for {
+		args, err := climenus.Prompt(menu, "Enter 'back' to return to previous menu, "+
+			"or 'scale X' to scale recipe by X", parseViewInput)
+		if err != nil {
+			return err
 		}
+		if args.Command == viewBackCommand {
+			return nil
+		}
+		fmt.Fprintln(out, scaleRecipe(&recipe, args.Float("factor")))
+	}
+}
+
+// Parses input at the prompt after a recipe is viewed as the scale or back command,
+// returns the args of the command entered
+func parseViewInput(input string) (*climenus.Args, error) {
+	words, err := climenus.Tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, errors.New("enter 'back' or 'scale X'")
 	}
 
-	return nil
+	switch words[0] {
+	case scaleCommand.Name:
+		return scaleCommand.ParseArgs(words)
+	case viewBackCommand.Name:
+		return viewBackCommand.ParseArgs(words)
+	}
+	return nil, fmt.Errorf("%q is not 'back' or 'scale X'", words[0])
 }
 
 // Scales the recipe currently being viewed by a multiplier value indicated
 // by user input.
-func scaleRecipe(recipe *Recipe, multiplierString string) (string, error) {
+func scaleRecipe(recipe *Recipe, multiplier float64) string {
 	scaledRecipeString := ""
 
-	multiplier, err := strconv.ParseFloat(multiplierString, 32)
-	if err != nil {
-		return "", err
-	}
 	for _, ingredient := range recipe.Ingredients {
 		scaledRecipeString += fmt.Sprintf("%s: %.2f %s\n", ingredient.Name,
 			ingredient.Quantity*float32(multiplier), ingredient.Unit)
 	}
 
-	return scaledRecipeString, nil
+	return scaledRecipeString
 }
 
 // Splits recipe step into multiple ines of console output based on the maxWidth